@@ -235,16 +235,26 @@ where
             // Note that this will fail if we are at a ringbuffer boundry, see
             // `try_recv` for details. In this demo this is not a problem
             // because the rx_buffer is always empty when this function is
-            // called and can thus always fit 40 bytes in a consecutive slice.
-            if let Some((eth_holder, withdraw_receiver)) = socket.recv(|x| {
-                if x.len() >= 40 {
-                    let eth_holder = Address(x[..20].try_into().unwrap());
-                    let withdraw_receiver = Address(x[20..40].try_into().unwrap());
-                    (40, Some((eth_holder, withdraw_receiver)))
+            // called and can thus always fit 44 bytes in a consecutive slice.
+            //
+            // The config is a single frame: the payload length (40) as
+            // big-endian u32, followed by both addresses.
+            if let Some(config) = socket.recv(|x| {
+                if x.len() >= 44 {
+                    let config = if u32::from_be_bytes(x[..4].try_into().unwrap()) == 40 {
+                        Ok((
+                            Address(x[4..24].try_into().unwrap()),
+                            Address(x[24..44].try_into().unwrap()),
+                        ))
+                    } else {
+                        Err(Error::UnexpectedMsg)
+                    };
+                    (44, Some(config))
                 } else {
                     (0, None)
                 }
             })? {
+                let (eth_holder, withdraw_receiver) = config?;
                 self.state = ApplicationState::ClosingSockets {
                     eth_holder,
                     withdraw_receiver,
@@ -301,7 +311,7 @@ where
             }
         }
 
-        let env: Envelope = match self.try_recv(self.participant_handle)? {
+        let env: Envelope = match self.try_recv(self.participant_handle, 2)? {
             Some(env) => env,
             None => return Ok(()),
         };
@@ -448,7 +458,14 @@ where
         Ok(())
     }
 
-    fn try_recv<T: Message + Default>(&mut self, handle: SocketHandle) -> Result<Option<T>, Error> {
+    /// Receives a message prefixed with its big endian length, which is
+    /// `prefix_len` bytes wide: 2 for go-perun participants (u16), 4 for the
+    /// remote watcher and funder (u32).
+    fn try_recv<T: Message + Default>(
+        &mut self,
+        handle: SocketHandle,
+        prefix_len: usize,
+    ) -> Result<Option<T>, Error> {
         // Yes, this function is long when including comments. When not
         // including them it is still complex, but I have not found a way to do
         // this without reading everything into a heap-allocated buffer or
@@ -458,15 +475,15 @@ where
         let socket = iface.get_socket::<TcpSocket>(handle);
 
         let recv_queue = socket.recv_queue();
-        if recv_queue < 2 {
-            return Ok(None); // We don't have 2 bytes of length
+        if recv_queue < prefix_len {
+            return Ok(None); // We don't have all bytes of the length
         }
 
         // Peek at the message length (keeping length and message in the
         // rx-buffer if it is not completely received)
-        let mut buf_msg_length = [0u8; 2];
-        let bytes_peeked = socket.peek_slice(&mut buf_msg_length)?;
-        if bytes_peeked < 2 {
+        let mut buf_msg_length = [0u8; 4];
+        let bytes_peeked = socket.peek_slice(&mut buf_msg_length[4 - prefix_len..])?;
+        if bytes_peeked < prefix_len {
             // smoltcp currently does not provide the capability to peek
             // over the edge of the (internal) rx ringbuffer. the current
             // peek cannot have this ability without copying data
@@ -495,10 +512,10 @@ where
             // which is currently < 1/512.
             panic!("Bug/Limitation in smoltcp");
         }
-        let length: usize = u16::from_be_bytes(buf_msg_length).into();
+        let length = u32::from_be_bytes(buf_msg_length) as usize;
 
         // Make sure it is even possible to receive the message.
-        if (2 + length) > socket.recv_capacity() {
+        if (prefix_len + length) > socket.recv_capacity() {
             // To handle messages larger than the rx_buffer size requires one of
             // the following:
             // - Partial protobuf decoding and storing the partial data
@@ -523,11 +540,11 @@ where
             // completion as long as the rx_buffer is large enough to hold the
             // largest possible message type (512 is sufficient for channels
             // with 2 participants and 1 asset).
-            return Err(Error::MessageLargerThanRxBuffer(2 + length));
+            return Err(Error::MessageLargerThanRxBuffer(prefix_len + length));
         }
 
         // Only continue if the message is complete.
-        if socket.recv_queue() < 2 + length {
+        if socket.recv_queue() < prefix_len + length {
             return Ok(None); // We don't have all the data
         }
 
@@ -544,18 +561,18 @@ where
         // Therefore we need to specify a size. We cannot take it from socket or
         // self.config because neither is constant => MAX_MESSAGE_SIZE
         //
-        // Discard 2 bytes of length information.
+        // Discard the bytes of length information.
         let read = socket.recv(|x| {
-            let len = x.len().min(2);
+            let len = x.len().min(prefix_len);
             (len, len)
         })?;
-        if read != 2 {
+        if read != prefix_len {
             // At the moment this cannot happen because we're panicking earlier
             // if we are at the bingbuffer boundry (the only situation where
             // this could happen). I've nevertheless added the logic to handle
             // this case as a defensive mechanism (i.e. we won't panic here) in
             // case someone fixes the panic above but doesn't change this part.
-            socket.recv(|_| (2 - read, ()))?;
+            socket.recv(|_| (prefix_len - read, ()))?;
         }
         let mut buf = [0u8; MAX_MESSAGE_SIZE];
         let bytes_read = socket.recv_slice(&mut buf[..length])?;
@@ -625,7 +642,7 @@ where
     }
 
     fn try_recv_participant_msg(&mut self) -> Result<Option<ParticipantMessage>, Error> {
-        let env: Envelope = match self.try_recv(self.participant_handle)? {
+        let env: Envelope = match self.try_recv(self.participant_handle, 2)? {
             Some(env) => env,
             None => return Ok(None),
         };
@@ -665,7 +682,7 @@ where
     }
 
     fn try_recv_service_msg(&mut self) -> Result<Option<ServiceReplyMessage>, Error> {
-        let env: perunwire::Message = match self.try_recv(self.service_handle)? {
+        let env: perunwire::Message = match self.try_recv(self.service_handle, 4)? {
             Some(env) => env,
             None => return Ok(None),
         };
@@ -114,6 +114,9 @@ mod net {
         // Some information about the (temporary) blockchain we need, could be hard
         // coded into the application or received by some other means.
         // let mut config_stream = TcpStream::connect("127.0.0.1:1339").unwrap();
+        // let mut len = [0u8; 4];
+        // config_stream.read_exact(&mut len).unwrap();
+        // assert_eq!(u32::from_be_bytes(len), 40);
         // let mut buf = [0u8; 20];
 
         // config_stream.read_exact(&mut buf).unwrap();
@@ -164,15 +167,17 @@ mod net {
         }
 
         pub fn recv_envelope(&self) -> perunwire::Envelope {
-            Self::recv_to(&self.stream)
+            // go-perun uses a big endian u16 for the length in bytes
+            Self::recv_to(&self.stream, 2)
         }
 
         pub fn recv_message(&self) -> perunwire::Message {
-            Self::recv_to(&self.remote_stream)
+            // the remote watcher and funder use a big endian u32
+            Self::recv_to(&self.remote_stream, 4)
         }
 
-        fn recv_to<T: Message + Default>(stream: &RefCell<TcpStream>) -> T {
-            let buf = Self::recv(stream);
+        fn recv_to<T: Message + Default>(stream: &RefCell<TcpStream>, prefix_len: usize) -> T {
+            let buf = Self::recv(stream, prefix_len);
 
             // Decode data (the Encoding Layer currently does not decode, so to
             // print stuff or call methods we have to do it at the moment).
@@ -182,12 +187,12 @@ mod net {
             msg
         }
 
-        fn recv(stream: &RefCell<TcpStream>) -> Vec<u8> {
+        fn recv(stream: &RefCell<TcpStream>, prefix_len: usize) -> Vec<u8> {
             let mut stream = stream.borrow_mut();
-            // big endian u16 for length in bytes
-            let mut buf = [0u8; 2];
-            stream.read_exact(&mut buf).unwrap();
-            let len = u16::from_be_bytes(buf);
+            // big endian length in bytes, prefix_len bytes wide
+            let mut buf = [0u8; 4];
+            stream.read_exact(&mut buf[4 - prefix_len..]).unwrap();
+            let len = u32::from_be_bytes(buf);
             // Protobuf encoded data
             let mut buf = vec![0u8; len as usize];
             stream.read_exact(&mut buf).unwrap();
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+)
+
+// AssetHolder is an asset of the example together with the depositor funding
+// it.
+type AssetHolder struct {
+	Name      string
+	Asset     *ethchannel.Asset
+	Depositor ethchannel.Depositor
+}
+
+// deploy_asset_holders deploys the ETH asset holder, named "eth", and an
+// ERC20 asset holder for each of the tokens, by name. The asset holders are
+// returned in that order, tokens sorted by name. Asset holders recorded in the
+// cache are reused.
+func deploy_asset_holders(
+	ctx context.Context,
+	cb ethchannel.ContractBackend,
+	cache *ContractCache,
+	adjAddr common.Address,
+	chain_id *big.Int,
+	deployer accounts.Account,
+	tokens map[string]common.Address,
+) ([]AssetHolder, error) {
+	asset := func(holder common.Address) *ethchannel.Asset {
+		return &ethchannel.Asset{
+			ChainID:     ethchannel.MakeChainID(chain_id),
+			AssetHolder: ethwallet.Address(holder),
+		}
+	}
+
+	eth_holder, err := cache.Deploy(ctx, cb, chain_id, fmt.Sprintf("eth-holder/%v", adjAddr),
+		func() (common.Address, error) {
+			return ethchannel.DeployETHAssetholder(ctx, cb, adjAddr, deployer)
+		})
+	if err != nil {
+		return nil, fmt.Errorf("deploying ETH asset holder: %w", err)
+	}
+	holders := []AssetHolder{{
+		Name:      "eth",
+		Asset:     asset(eth_holder),
+		Depositor: ethchannel.NewETHDepositor(),
+	}}
+
+	names := make([]string, 0, len(tokens))
+	for name := range tokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		token := tokens[name]
+		holder, err := cache.Deploy(ctx, cb, chain_id, fmt.Sprintf("erc20-holder/%v/%v", adjAddr, token),
+			func() (common.Address, error) {
+				return ethchannel.DeployERC20Assetholder(ctx, cb, adjAddr, token, deployer)
+			})
+		if err != nil {
+			return nil, fmt.Errorf("deploying asset holder of token %s: %w", name, err)
+		}
+		holders = append(holders, AssetHolder{
+			Name:      name,
+			Asset:     asset(holder),
+			Depositor: ethchannel.NewERC20Depositor(token),
+		})
+	}
+	return holders, nil
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccountConfig selects which wallet accounts are used for the different
+// on-chain roles of the example. Keeping them separate allows the account
+// paying the deposits to be different from the one deploying the contracts.
+type AccountConfig struct {
+	// Deployer deploys the adjudicator and asset holder contracts.
+	Deployer accounts.Account
+	// Funder pays the channel deposits into the asset holder.
+	Funder accounts.Account
+	// Adjudicator sends the register/withdraw transactions.
+	Adjudicator accounts.Account
+	// Receiver receives the funds withdrawn from the asset holder. If unset,
+	// the Funder address is used.
+	Receiver common.Address
+}
+
+// Validate checks that all configured accounts are contained in the wallet
+// and fills in the defaults for optional fields.
+func (c *AccountConfig) Validate(w accounts.Wallet) error {
+	for _, acc := range []struct {
+		role    string
+		account accounts.Account
+	}{
+		{"deployer", c.Deployer},
+		{"funder", c.Funder},
+		{"adjudicator", c.Adjudicator},
+	} {
+		if !w.Contains(acc.account) {
+			return fmt.Errorf("%s account %v not found in wallet", acc.role, acc.account.Address)
+		}
+	}
+	if c.Receiver == (common.Address{}) {
+		c.Receiver = c.Funder.Address
+	}
+	return nil
+}
+
+// Accounts returns all configured accounts, e.g. for pre-funding them on a
+// simulated backend.
+func (c AccountConfig) Accounts() []accounts.Account {
+	return []accounts.Account{c.Adjudicator, c.Deployer, c.Funder}
+}
+
+// ListenConfig holds the addresses the example listens on.
+type ListenConfig struct {
+	// Bus is the address of the perun wire bus listener.
+	Bus string
+	// Remote is the address of the remote watcher/funder server.
+	Remote string
+	// Info is the address of the server sending out the contract addresses.
+	Info string
+	// Control is the address of the control service.
+	Control string
+	// ControlTokenFile is the file containing the token control connections
+	// have to authenticate with, authentication is disabled if empty.
+	ControlTokenFile string
+	// Metrics is the address of the Prometheus metrics server, which is
+	// disabled if empty.
+	Metrics string
+}
+
+// DefaultListenConfig returns the addresses expected by the Rust side of the
+// example.
+func DefaultListenConfig() ListenConfig {
+	return ListenConfig{
+		Bus:     ":1337",
+		Remote:  ":1338",
+		Info:    ":1339",
+		Control: ":2222",
+	}
+}
+
+// RegisterFlags registers command line flags overriding the listen
+// addresses, which allows running multiple instances on the same host.
+func (c *ListenConfig) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Bus, "bus-addr", c.Bus, "listen address of the perun wire bus")
+	fs.StringVar(&c.Remote, "remote-addr", c.Remote, "listen address of the remote watcher/funder server")
+	fs.StringVar(&c.Info, "info-addr", c.Info, "listen address of the contract info server")
+	fs.StringVar(&c.Control, "control-addr", c.Control, "listen address of the control service")
+	fs.StringVar(&c.ControlTokenFile, "control-token-file", c.ControlTokenFile, "file containing the token required by the control service (disabled if empty)")
+	fs.StringVar(&c.Metrics, "metrics-addr", c.Metrics, "listen address of the Prometheus metrics server (disabled if empty)")
+}
+
+// ChainAddresses maps chain IDs to contract addresses. As a flag it is given
+// as chain-id=address, repeatedly.
+type ChainAddresses map[uint64]common.Address
+
+var _ flag.Value = (ChainAddresses)(nil)
+
+func (c ChainAddresses) String() string {
+	chains := make([]uint64, 0, len(c))
+	for chain := range c {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	entries := make([]string, len(chains))
+	for i, chain := range chains {
+		entries[i] = fmt.Sprintf("%d=%v", chain, c[chain])
+	}
+	return strings.Join(entries, ",")
+}
+
+func (c ChainAddresses) Set(value string) error {
+	chain, addr, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected chain-id=address, got %q", value)
+	}
+	id, err := strconv.ParseUint(chain, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chain ID %q: %w", chain, err)
+	}
+	if !common.IsHexAddress(addr) {
+		return fmt.Errorf("invalid address %q", addr)
+	}
+	c[id] = common.HexToAddress(addr)
+	return nil
+}
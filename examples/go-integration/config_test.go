@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAccountConfigValidate(t *testing.T) {
+	w := NewSimpleWallet()
+	deployer := w.GenerateNewAccount()
+	funder := w.GenerateNewAccount()
+	adjudicator := w.GenerateNewAccount()
+	// Not imported into the wallet.
+	foreign := accounts.Account{Address: common.HexToAddress("0x00000000000000000000000000000000000000ff")}
+	receiver := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+	tests := []struct {
+		name         string
+		cfg          AccountConfig
+		wantErr      string // Substring of the error, empty for none.
+		wantReceiver common.Address
+	}{
+		{
+			name:         "distinct accounts",
+			cfg:          AccountConfig{Deployer: deployer, Funder: funder, Adjudicator: adjudicator},
+			wantReceiver: funder.Address,
+		},
+		{
+			name:         "explicit receiver",
+			cfg:          AccountConfig{Deployer: deployer, Funder: funder, Adjudicator: adjudicator, Receiver: receiver},
+			wantReceiver: receiver,
+		},
+		{
+			name:    "unknown deployer",
+			cfg:     AccountConfig{Deployer: foreign, Funder: funder, Adjudicator: adjudicator},
+			wantErr: "deployer account",
+		},
+		{
+			name:    "unknown funder",
+			cfg:     AccountConfig{Deployer: deployer, Funder: foreign, Adjudicator: adjudicator},
+			wantErr: "funder account",
+		},
+		{
+			name:    "unknown adjudicator",
+			cfg:     AccountConfig{Deployer: deployer, Funder: funder, Adjudicator: foreign},
+			wantErr: "adjudicator account",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := cfg.Validate(w)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validating: %v", err)
+			}
+			if cfg.Receiver != tt.wantReceiver {
+				t.Errorf("receiver %v, want %v", cfg.Receiver, tt.wantReceiver)
+			}
+		})
+	}
+}
+
+func TestAccountConfigAccounts(t *testing.T) {
+	w := NewSimpleWallet()
+	cfg := AccountConfig{
+		Deployer:    w.GenerateNewAccount(),
+		Funder:      w.GenerateNewAccount(),
+		Adjudicator: w.GenerateNewAccount(),
+	}
+	seen := make(map[common.Address]bool)
+	for _, acc := range cfg.Accounts() {
+		seen[acc.Address] = true
+	}
+	for _, acc := range []accounts.Account{cfg.Deployer, cfg.Funder, cfg.Adjudicator} {
+		if !seen[acc.Address] {
+			t.Errorf("account %v missing from Accounts()", acc.Address)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CodeReader reads the code of a contract, e.g. an ethclient.Client.
+type CodeReader interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// ContractCache records the addresses of deployed contracts per chain ID in a
+// JSON file, so that later runs reuse them instead of deploying again. A nil
+// cache deploys every time.
+type ContractCache struct {
+	path string
+
+	mu sync.Mutex
+	// Addresses by chain ID and contract key.
+	contracts map[string]map[string]common.Address
+}
+
+// LoadContractCache loads the cache from the file at path. A missing file is
+// an empty cache.
+func LoadContractCache(path string) (*ContractCache, error) {
+	c := &ContractCache{path: path, contracts: make(map[string]map[string]common.Address)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading contract cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.contracts); err != nil {
+		return nil, fmt.Errorf("decoding contract cache: %w", err)
+	}
+	return c, nil
+}
+
+// Deploy returns the cached address of the contract if there is code at it,
+// otherwise it deploys the contract and caches its address. The key has to
+// include the addresses of the contracts the deployed one depends on, so that
+// it is deployed again if they change.
+func (c *ContractCache) Deploy(
+	ctx context.Context,
+	backend CodeReader,
+	chain_id *big.Int,
+	key string,
+	deploy func() (common.Address, error),
+) (common.Address, error) {
+	if c == nil {
+		return deploy()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chain := chain_id.String()
+	if addr, ok := c.contracts[chain][key]; ok {
+		code, err := backend.CodeAt(ctx, addr, nil)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("reading code of cached %s: %w", key, err)
+		}
+		if len(code) > 0 {
+			fmt.Printf("Reusing %s at %v\n", key, addr)
+			return addr, nil
+		}
+	}
+
+	addr, err := deploy()
+	if err != nil {
+		return common.Address{}, err
+	}
+	if c.contracts[chain] == nil {
+		c.contracts[chain] = make(map[string]common.Address)
+	}
+	c.contracts[chain][key] = addr
+	return addr, c.save()
+}
+
+// save must be called with c.mu held.
+func (c *ContractCache) save() error {
+	data, err := json.MarshalIndent(c.contracts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding contract cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing contract cache: %w", err)
+	}
+	return nil
+}
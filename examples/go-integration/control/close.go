@@ -0,0 +1,80 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+)
+
+// closeTimeout bounds how long close-all waits for the peer to accept the
+// final state of a channel before force-closing it.
+const closeTimeout = 30 * time.Second
+
+// closeAll closes all tracked open channels one by one, reporting the result
+// of each. Like Shutdown, sub- and virtual channels are closed before the
+// ledger channels funding them. Channels are taken by ID, so channels tracked
+// in the meantime do not shift the ones to close.
+func (s *ControlService) closeAll(w *bufio.Writer) error {
+	var ledger, other []*client.Channel
+	for _, id := range s.trackedIDs() {
+		ch, err := s.client.Channel(id)
+		if err != nil || ch.IsClosed() {
+			continue
+		}
+		if ch.IsLedgerChannel() {
+			ledger = append(ledger, ch)
+		} else {
+			other = append(other, ch)
+		}
+	}
+
+	var failed int
+	for _, ch := range append(other, ledger...) {
+		how, err := s.closeChannel(ch)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "0x%x: failed: %v\n", ch.ID(), err)
+		} else {
+			fmt.Fprintf(w, "0x%x: %s\n", ch.ID(), how)
+		}
+		w.Flush()
+	}
+	if failed > 0 {
+		return fmt.Errorf("Failed to close %d channel(s)", failed)
+	}
+	return nil
+}
+
+// closeChannel closes the channel collaboratively, falling back to a
+// force-close if the peer does not accept the final state in time. It
+// returns how the channel was closed.
+func (s *ControlService) closeChannel(ch *client.Channel) (string, error) {
+	ctx, done := s.startOp("close", ch.ID())
+	defer done()
+	defer s.lockChannel(ch.ID())()
+
+	if ch.IsClosed() {
+		return "already closed", nil
+	}
+	how := "closed"
+	if !ch.State().IsFinal {
+		updateCtx, cancel := context.WithTimeout(ctx, closeTimeout)
+		err := ch.Update(updateCtx, func(state *channel.State) {
+			state.IsFinal = true
+		})
+		cancel()
+		if err != nil {
+			how = fmt.Sprintf("force-closed (final state not accepted: %v)", err)
+		}
+	}
+	// Settling a non-final state registers it and withdraws after the
+	// dispute timeout.
+	if err := s.settleLocked(ctx, ch); err != nil {
+		return "", err
+	}
+	return how, nil
+}
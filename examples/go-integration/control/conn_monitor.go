@@ -0,0 +1,154 @@
+package control
+
+import (
+	"context"
+	"sync"
+
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+)
+
+// ConnectionMonitor wraps the dialer and listener of a wire bus to report when
+// peers connect and disconnect. A peer is connected from the first envelope
+// received on one of its connections until all of them are closed.
+type ConnectionMonitor struct {
+	mu      sync.Mutex
+	conns   map[string]int // Open connections per peer, see peerKey.
+	handler func(peer wire.Address, connected bool)
+}
+
+// NewConnectionMonitor creates a ConnectionMonitor. Pass the dialer and
+// listener of the bus through Dialer and Listener.
+func NewConnectionMonitor() *ConnectionMonitor {
+	return &ConnectionMonitor{conns: make(map[string]int)}
+}
+
+// OnPeerChange sets the handler called when a peer becomes connected or
+// disconnected. It must not block.
+func (m *ConnectionMonitor) OnPeerChange(handler func(peer wire.Address, connected bool)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handler = handler
+}
+
+// Dialer wraps the dialer so that its connections are monitored.
+func (m *ConnectionMonitor) Dialer(d wirenet.Dialer) wirenet.Dialer {
+	return &monitoredDialer{Dialer: d, monitor: m}
+}
+
+// Listener wraps the listener so that its connections are monitored.
+func (m *ConnectionMonitor) Listener(l wirenet.Listener) wirenet.Listener {
+	return &monitoredListener{Listener: l, monitor: m}
+}
+
+func (m *ConnectionMonitor) opened(peer wire.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := peerKey(peer)
+	m.conns[key]++
+	if m.conns[key] == 1 && m.handler != nil {
+		m.handler(peer, true)
+	}
+}
+
+func (m *ConnectionMonitor) closed(peer wire.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := peerKey(peer)
+	m.conns[key]--
+	if m.conns[key] > 0 {
+		return
+	}
+	delete(m.conns, key)
+	if m.handler != nil {
+		m.handler(peer, false)
+	}
+}
+
+type monitoredDialer struct {
+	wirenet.Dialer
+	monitor *ConnectionMonitor
+}
+
+func (d *monitoredDialer) Dial(ctx context.Context, addr wire.Address, ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	conn, err := d.Dialer.Dial(ctx, addr, ser)
+	if err != nil {
+		return nil, err
+	}
+	return &monitoredConn{Conn: conn, monitor: d.monitor}, nil
+}
+
+type monitoredListener struct {
+	wirenet.Listener
+	monitor *ConnectionMonitor
+}
+
+func (l *monitoredListener) Accept(ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	conn, err := l.Listener.Accept(ser)
+	if err != nil {
+		return nil, err
+	}
+	return &monitoredConn{Conn: conn, monitor: l.monitor}, nil
+}
+
+// monitoredConn learns its peer from the sender of the first received
+// envelope, which is the peer's authentication. Failing connections close
+// themselves, so a failed Recv or Send counts as closing.
+type monitoredConn struct {
+	wirenet.Conn
+	monitor *ConnectionMonitor
+
+	mu     sync.Mutex
+	peer   wire.Address // nil until the first envelope is received
+	isDone bool
+}
+
+func (c *monitoredConn) Recv() (*wire.Envelope, error) {
+	env, err := c.Conn.Recv()
+	if err != nil {
+		c.done()
+		return nil, err
+	}
+	c.identify(env.Sender)
+	return env, nil
+}
+
+func (c *monitoredConn) Send(env *wire.Envelope) error {
+	err := c.Conn.Send(env)
+	if err != nil {
+		c.done()
+	}
+	return err
+}
+
+func (c *monitoredConn) Close() error {
+	c.done()
+	return c.Conn.Close()
+}
+
+func (c *monitoredConn) identify(peer wire.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.peer != nil || c.isDone {
+		return
+	}
+	c.peer = peer
+	c.monitor.opened(peer)
+}
+
+func (c *monitoredConn) done() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.isDone {
+		return
+	}
+	c.isDone = true
+	if c.peer != nil {
+		c.monitor.closed(c.peer)
+	}
+}
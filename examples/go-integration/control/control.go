@@ -3,45 +3,299 @@ package control
 import (
 	"bufio"
 	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	remote "go-integration/perun-remote"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/perun-network/perun-eth-backend/bindings/assetholder"
 	ethchannel "github.com/perun-network/perun-eth-backend/channel"
 	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/client"
 	"perun.network/go-perun/wire"
 	"perun.network/go-perun/wire/net/simple"
+	perunProto "perun.network/go-perun/wire/protobuf"
 )
 
 type ControlService struct {
+	// mu guards the channel list and the bookkeeping maps below. It is only
+	// held briefly; operations on a channel are serialized by its entry in
+	// chLocks instead, so a slow operation does not block other channels.
 	mu          sync.Mutex
 	channelsIds []channel.ID
+	// Names given to channels on proposal, usable instead of the index.
+	aliases     map[string]channel.ID
+	chLocks     map[channel.ID]*sync.Mutex
 	client      *client.Client
-	eth_holder  common.Address
+	perunID     wire.Address
 	participant common.Address
+	listenAddr  string
+	dialer      *simple.Dialer
+	// Peers registered with the dialer, in order of registration.
+	peers []namedPeer
+	// Assets which can be used in proposals, in order of registration.
+	assets []namedAsset
+
+	// Channels reported by the client, including ones that were opened or
+	// restored without going through the control service. Guarded by its own
+	// mutex, as the client reports new channels while s.mu may be held.
+	knownMu       sync.Mutex
+	knownChannels []*client.Channel
+
+	chain *chainAccess
+	// Registers states on disputes, nil if not enabled.
+	disputes *disputeAccess
+	// Records the signed states of the client, nil if not recorded.
+	txs *TxRecorder
+	// Token control connections have to send before any command, nil if
+	// authentication is disabled.
+	token []byte
+	// Funding agreements of the channel proposals, per channel.
+	agreements map[channel.ID]channel.Balances
+	// Sessions of control connections by token, see session.
+	sessions map[string]*session
+	// Last errors of background operations on the channels and their total
+	// count.
+	channelErrs     map[channel.ID]error
+	channelErrCount uint64
+	// Number of processed commands.
+	cmdCount uint64
+	// Running operations by ID, see operation.
+	ops    map[int]*operation
+	nextOp int
+	// Funds channels for the fund command, nil if not configured.
+	funder *remote.FunderService
+	// Subscribers to the adjudicator events, per channel (see watch command).
+	eventSubs map[channel.ID][]chan channel.AdjudicatorEvent
+	// On-chain settlement progress, per channel.
+	settlement map[channel.ID]settlementState
+	// The latest states, per channel (see history command).
+	history map[channel.ID]*stateHistory
+}
+
+type namedPeer struct {
+	alias string
+	host  string
+}
+
+type namedAsset struct {
+	name      string
+	asset     channel.Asset
+	depositor ethchannel.Depositor // Used by the deposit command, nil if not depositable.
 }
 
-func NewControlService(cl *client.Client, eth_holder common.Address, participant common.Address) ControlService {
+// chainAccess holds what is needed to query the chain and send deposits.
+type chainAccess struct {
+	cb          ethchannel.ContractBackend
+	account     accounts.Account
+	adjudicator common.Address
+}
+
+// NewControlService creates the control service of a client on the chain with
+// the given ID. The ETH asset holders are given per chain ID: the one of the
+// client's chain is available in proposals as "eth", each one also as
+// "eth@<chain-id>". Deposits are only possible on the client's chain.
+func NewControlService(cl *client.Client, perunID wire.Address, dialer *simple.Dialer, chain_id uint64, eth_holders map[uint64]common.Address, participant common.Address, listenAddr string) ControlService {
+	ethAsset := func(chain uint64) *ethchannel.Asset {
+		return &ethchannel.Asset{
+			ChainID:     ethchannel.MakeChainID(new(big.Int).SetUint64(chain)),
+			AssetHolder: ethwallet.Address(eth_holders[chain]),
+		}
+	}
+	assets := []namedAsset{{name: "eth", asset: ethAsset(chain_id), depositor: ethchannel.NewETHDepositor()}}
+	chains := make([]uint64, 0, len(eth_holders))
+	for chain := range eth_holders {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+	for _, chain := range chains {
+		var depositor ethchannel.Depositor
+		if chain == chain_id {
+			depositor = ethchannel.NewETHDepositor()
+		}
+		assets = append(assets, namedAsset{name: fmt.Sprintf("eth@%d", chain), asset: ethAsset(chain), depositor: depositor})
+	}
 	return ControlService{
 		mu:          sync.Mutex{},
 		channelsIds: make([]channel.ID, 0),
+		aliases:     make(map[string]channel.ID),
+		chLocks:     make(map[channel.ID]*sync.Mutex),
 		client:      cl,
-		eth_holder:  eth_holder,
+		perunID:     perunID,
+		dialer:      dialer,
 		participant: participant,
+		listenAddr:  listenAddr,
+		agreements:  make(map[channel.ID]channel.Balances),
+		sessions:    make(map[string]*session),
+		ops:         make(map[int]*operation),
+		channelErrs: make(map[channel.ID]error),
+		eventSubs:   make(map[channel.ID][]chan channel.AdjudicatorEvent),
+		settlement:  make(map[channel.ID]settlementState),
+		history:     make(map[channel.ID]*stateHistory),
+		assets:      assets,
+	}
+}
+
+// RegisterAsset makes an asset (e.g. an ERC20 token asset holder) available
+// for proposals under the given name. The depositor is used for deposits into
+// channels of the asset. Registering an existing name replaces the asset.
+func (s *ControlService) RegisterAsset(name string, asset channel.Asset, depositor ethchannel.Depositor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.assets {
+		if s.assets[i].name == name {
+			s.assets[i].asset = asset
+			s.assets[i].depositor = depositor
+			return
+		}
+	}
+	s.assets = append(s.assets, namedAsset{name: name, asset: asset, depositor: depositor})
+}
+
+// AddPeer registers the host (host:port) of the peer with the wire address
+// alias with the dialer. Adding an existing alias replaces its host.
+func (s *ControlService) AddPeer(alias string, host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dialer.Register(simple.NewAddress(alias), host)
+	for i := range s.peers {
+		if s.peers[i].alias == alias {
+			s.peers[i].host = host
+			return
+		}
+	}
+	s.peers = append(s.peers, namedPeer{alias: alias, host: host})
+}
+
+func (s *ControlService) lookupAsset(name string) (channel.Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.assets {
+		if a.name == name {
+			return a.asset, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown asset: %s", name)
+}
+
+// depositorOf returns the depositor registered for the asset.
+func (s *ControlService) depositorOf(asset channel.Asset) (ethchannel.Depositor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.assets {
+		if a.asset.Equal(asset) && a.depositor != nil {
+			return a.depositor, nil
+		}
+	}
+	return nil, errors.New("No depositor registered for the asset")
+}
+
+// assetName returns the name under which the asset was registered or
+// "<unknown>".
+func (s *ControlService) assetName(asset channel.Asset) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.assets {
+		if a.asset.Equal(asset) {
+			return a.name
+		}
+	}
+	return "<unknown>"
+}
+
+// EnableChainAccess enables the commands interacting with the chain directly,
+// like deposit, which sends deposits from the given account, and estimate,
+// which estimates the calls to the adjudicator contract.
+func (s *ControlService) EnableChainAccess(cb ethchannel.ContractBackend, account accounts.Account, adjudicator common.Address) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chain = &chainAccess{cb: cb, account: account, adjudicator: adjudicator}
+}
+
+// RecordTransactions makes the signed states recorded by the recorder
+// available for registering disputes and to the export command.
+func (s *ControlService) RecordTransactions(txs *TxRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.txs = txs
+}
+
+// RequireToken makes control connections authenticate by sending the token as
+// their first line. Connections sending a different token are closed.
+func (s *ControlService) RequireToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = []byte(token)
+}
+
+// RegisterFundingAgreement records the funding agreement of the proposal
+// which opened the channel, for the funding-agreement command.
+func (s *ControlService) RegisterFundingAgreement(id channel.ID, agreement channel.Balances) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.agreements[id] = agreement
+}
+
+// HandleNewChannel records a channel created or restored by the client so it
+// can later be picked up by the resync command. Pass it to
+// client.OnNewChannel. The channel is forgotten once it is closed.
+func (s *ControlService) HandleNewChannel(ch *client.Channel) {
+	s.knownMu.Lock()
+	s.knownChannels = append(s.knownChannels, ch)
+	s.knownMu.Unlock()
+
+	// Registered after adding the channel, so that it is also forgotten if it
+	// was closed in between.
+	if !ch.OnClose(func() { s.forgetChannel(ch) }) {
+		s.forgetChannel(ch)
 	}
 }
 
+func (s *ControlService) forgetChannel(ch *client.Channel) {
+	s.knownMu.Lock()
+	defer s.knownMu.Unlock()
+
+	for i, known := range s.knownChannels {
+		if known == ch {
+			s.knownChannels = append(s.knownChannels[:i], s.knownChannels[i+1:]...)
+			return
+		}
+	}
+}
+
+// Run listens on the configured address and serves control connections. It
+// only returns on errors.
 func (s *ControlService) Run() error {
-	l, err := net.Listen("tcp", ":2222")
+	l, err := net.Listen("tcp", s.listenAddr)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("listening on %s: %w", s.listenAddr, err)
 	}
 	for {
 		conn, err := l.Accept()
@@ -53,6 +307,7 @@ func (s *ControlService) Run() error {
 }
 
 func (s *ControlService) connHandler(conn net.Conn) {
+	defer conn.Close()
 	r := bufio.NewScanner(conn)
 	w := bufio.NewWriter(conn)
 	writeString := func(str string) {
@@ -65,69 +320,238 @@ func (s *ControlService) connHandler(conn net.Conn) {
 			panic(err)
 		}
 	}
-	writeString("Participant control service\nWrite h for help\n> ")
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+	if token != nil {
+		writeString("Token: ")
+		if !r.Scan() || subtle.ConstantTimeCompare(r.Bytes(), token) != 1 {
+			writeString("Authentication failed\n")
+			return
+		}
+	}
+	sess, err := s.newSession()
+	if err != nil {
+		writeString(err.Error() + "\n")
+		return
+	}
+	writeString("Participant control service\nWrite h for help\n" +
+		"Session " + sess.token + " (continue it after a reconnect with: resume " + sess.token + ")\n> ")
 	for r.Scan() {
 		cmd := r.Text()
 		if cmd == "q" || cmd == "quit" {
 			break
 		}
-		err := s.processCmd(cmd, w)
-		if err != nil {
-			writeString(err.Error())
+		if strings.HasPrefix(cmd, "resume ") {
+			if resumed, err := s.resumeSession(strings.TrimPrefix(cmd, "resume ")); err != nil {
+				writeString(err.Error() + "\n")
+			} else {
+				sess = resumed
+				writeString("Resumed session " + sess.token + "\n")
+			}
+			writeString("> ")
+			continue
+		}
+		s.touch(sess)
+		s.countCommand()
+		// Commands return their errors instead of writing them, so each
+		// error is written exactly once, here.
+		if err := s.processCmd(cmd, sess, r, w); err != nil {
+			writeString(err.Error() + "\n")
+			if errors.As(err, new(*usageError)) {
+				writeString("Usage: " + usage(strings.Split(cmd, " ")[0]) + "\n")
+			}
 		}
 		writeString("> ")
 	}
 }
 
-func (s *ControlService) processCmd(cmd string, w *bufio.Writer) error {
-	writeString := func(str string) {
-		_, err := w.WriteString(str)
-		if err != nil {
-			panic(err)
-		}
-		err = w.Flush()
-		if err != nil {
-			panic(err)
-		}
-	}
+func (s *ControlService) countCommand() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cmdCount++
+}
 
+// CommandCount returns the number of commands processed since the start,
+// e.g. for metrics.
+func (s *ControlService) CommandCount() uint64 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.cmdCount
+}
+
+func (s *ControlService) processCmd(cmd string, sess *session, r *bufio.Scanner, w *bufio.Writer) error {
 	c := strings.Split(cmd, " ")
 	cmd = c[0]
 	args := c[1:]
 
 	switch cmd {
 	case "h", "help":
-		writeString("" +
-			"  h, help                  Print this message\n" +
-			"  q, quit                  Exit the control service (the go-side is still running afterwards)\n" +
-			"  p, propose               Propose a channel\n" +
-			"  u, update [<index>]      Update the current channel\n" +
-			"  c, close [<index>]       Close the channel\n" +
-			"  f, force-close [<index>] Force close the channel\n" +
-			"  s, status                Short status report on the channel\n",
-		)
+		writeHelp(w)
 	case "p", "propose":
-		err := s.propose_channel()
+		asset, name := "eth", ""
+		for i := 0; i < len(args); i++ {
+			if args[i] == "--name" && i+1 < len(args) {
+				name = args[i+1]
+				i++
+			} else {
+				asset = args[i]
+			}
+		}
+		return s.propose_channel(asset, name)
+	case "ps", "propose-sub":
+		return s.dispatch_with_index_and_amount(sess, args, nil, s.propose_sub_channel)
+	case "pv", "propose-virtual":
+		if len(args) != 3 {
+			return usageErrorf("Invalid argument count")
+		}
+		index, err := s.resolveIndex(args[0])
+		if err != nil {
+			return err
+		}
+		peerParent, err := parseChannelID(args[1])
 		if err != nil {
-			writeString(err.Error())
+			return err
+		}
+		amount, err := parseAmount(args[2])
+		if err != nil {
+			return err
 		}
+		return s.propose_virtual_channel(index, peerParent, amount)
 	case "u", "update":
-		return s.dispatch_with_index_default_last(args, func(index int) error {
-			return s.update(index, 100, false)
+		if len(args) == 4 {
+			index, err := s.resolveIndex(args[0])
+			if err != nil {
+				return err
+			}
+			var parts [2]int
+			for i := range parts {
+				if parts[i], err = strconv.Atoi(args[i+1]); err != nil || parts[i] < 0 {
+					return usageErrorf("Participant must be a non-negative integer, got %q", args[i+1])
+				}
+			}
+			amount, err := parseAmount(args[3])
+			if err != nil {
+				return err
+			}
+			return s.transfer(index, parts[0], parts[1], amount)
+		}
+		return s.dispatch_with_index_and_amount(sess, args, big.NewInt(100), func(index int, amount *big.Int) error {
+			return s.update(index, amount, false)
 		})
 	case "c", "close":
-		return s.dispatch_with_index_default_last(args, func(index int) error {
-			return s.update(index, 0, true)
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.update(index, big.NewInt(0), true)
+		})
+	case "close-all":
+		if len(args) != 0 {
+			return usageErrorf("Invalid argument count")
+		}
+		return s.closeAll(w)
+	case "d", "deposit":
+		return s.dispatch_with_index_and_amount(sess, args, nil, s.deposit)
+	case "fund":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.fund(index, w)
+		})
+	case "funding-agreement":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.printFundingAgreement(index, w)
+		})
+	case "history":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.printHistory(index, w)
+		})
+	case "export":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.exportSignedState(index, w)
+		})
+	case "w", "watch":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.watchEvents(index, r, w)
+		})
+	case "conclude":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.conclude(index, w)
+		})
+	case "estimate":
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.printEstimate(index, w)
 		})
 	case "f", "force-close":
-		return s.dispatch_with_index_default_last(args, s.force_close_channel)
+		args, gas, err := parseGasFlags(args)
+		if err != nil {
+			return err
+		}
+		if len(args) == 2 {
+			index, err := s.resolveIndex(args[0])
+			if err != nil {
+				return err
+			}
+			version, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return usageErrorf("Version must be a non-negative integer, got %q", args[1])
+			}
+			return s.force_close_channel_at(index, &version, gas)
+		}
+		return s.dispatch_with_index_default_last(sess, args, func(index int) error {
+			return s.force_close_channel_at(index, nil, gas)
+		})
 	case "s", "status":
+		if len(args) == 1 && args[0] == "--json" {
+			return s.printStatusJSON(w)
+		}
 		s.printStatus(w)
+	case "sj":
+		return s.printStatusJSON(w)
+	case "r", "resync":
+		s.resync(w)
+	case "ops":
+		s.printOps(w)
+	case "cancel":
+		if len(args) != 1 {
+			return usageErrorf("Invalid argument count")
+		}
+		opID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return usageErrorf("Operation ID must be an integer, got %q", args[0])
+		}
+		return s.cancelOp(opID)
+	case "use":
+		if len(args) != 1 {
+			return usageErrorf("Invalid argument count")
+		}
+		index, err := s.resolveIndex(args[0])
+		if err != nil {
+			return err
+		}
+		return s.selectChannel(sess, index)
+	case "peer":
+		return s.peerCmd(args, w)
+	case "ping":
+		if len(args) != 1 {
+			return usageErrorf("Invalid argument count")
+		}
+		return s.pingPeer(args[0], w)
+	case "addr":
+		s.printAddresses(w)
+	case "addrs":
+		switch len(args) {
+		case 0:
+			s.printAllParticipants(w)
+		case 1:
+			index, err := s.resolveIndex(args[0])
+			if err != nil {
+				return err
+			}
+			return s.printParticipants(index, w)
+		default:
+			return usageErrorf("Invalid argument count")
+		}
 	default:
-		writeString("Unknown command\n")
+		return fmt.Errorf("Unknown command: %s (write h for help)", cmd)
 	}
 	return nil
 }
@@ -141,42 +565,91 @@ func (s *ControlService) RegisterChannel(ch *client.Channel) {
 
 func (s *ControlService) registerChannel(ch *client.Channel) {
 	s.channelsIds = append(s.channelsIds, ch.ID())
+	s.recordState(ch.State())
 	ch.OnUpdate(func(from, to *channel.State) {
+		s.mu.Lock()
+		s.recordState(to)
+		s.mu.Unlock()
+
 		if to.IsFinal {
 			go func() {
-				err := ch.Settle(context.Background(), false)
-				if err != nil {
-					panic(err)
-				}
+				// Serialized with the other operations on the channel, e.g.
+				// Shutdown finalizing and settling it.
+				defer s.lockChannel(ch.ID())()
+				s.settleWithRetry(ch)
 			}()
 		}
 	})
 	go func() {
-		err := ch.Watch(adjudicatorEventHandler{channel: ch})
+		// Watching ends when the channel is closed.
+		opID := s.trackOp("watch", ch.ID(), nil)
+		defer s.untrackOp(opID)
+		err := ch.Watch(adjudicatorEventHandler{channel: ch, service: s})
 		if err != nil {
-			panic(err)
+			log.Errorf("Watching channel 0x%x failed: %v", ch.ID(), err)
+			s.recordChannelError(ch.ID(), fmt.Errorf("watching: %w", err))
 		}
 	}()
 }
 
-func (s *ControlService) propose_channel() error {
-	peers := []wire.Address{simple.NewAddress("Alice"), simple.NewAddress("Bob")}
+func (s *ControlService) isTracked(id channel.ID) bool {
+	for _, tracked := range s.channelsIds {
+		if tracked == id {
+			return true
+		}
+	}
+	return false
+}
+
+// resync registers all open channels known to the client that are not yet
+// tracked by the control service, e.g. because they were opened out of band.
+func (s *ControlService) resync(w io.Writer) {
+	s.knownMu.Lock()
+	known := make([]*client.Channel, len(s.knownChannels))
+	copy(known, s.knownChannels)
+	s.knownMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := 0
+	for _, ch := range known {
+		if ch.IsClosed() || s.isTracked(ch.ID()) {
+			continue
+		}
+		s.registerChannel(ch)
+		fmt.Fprintf(w, "Tracking channel 0x%x at index %d\n", ch.ID(), len(s.channelsIds)-1)
+		added++
+	}
+	fmt.Fprintf(w, "Resync done, %d channel(s) added\n", added)
+}
+
+// propose_channel proposes a ledger channel with Bob over the assets, given as
+// comma separated names. If name is not empty, the channel can be referred to
+// by it in later commands.
+func (s *ControlService) propose_channel(assetNames string, name string) error {
+	var (
+		assets   []channel.Asset
+		balances [][]*big.Int
+	)
+	for _, assetName := range strings.Split(assetNames, ",") {
+		asset, err := s.lookupAsset(assetName)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, asset)
+		balances = append(balances, []*big.Int{big.NewInt(100_000), big.NewInt(100_000)})
+	}
+	if name != "" {
+		if err := s.checkAlias(name); err != nil {
+			return err
+		}
+	}
+	peers := []wire.Address{s.perunID, simple.NewAddress("Bob")}
 	initBals := &channel.Allocation{
-		Assets: []channel.Asset{
-			&ethchannel.Asset{
-				ChainID: ethchannel.ChainID{
-					Int: big.NewInt(1337),
-				},
-				AssetHolder: ethwallet.Address(s.eth_holder),
-			},
-		},
-		Balances: [][]*big.Int{
-			{
-				big.NewInt(100_000),
-				big.NewInt(100_000),
-			},
-		},
-		Locked: []channel.SubAlloc{},
+		Assets:   assets,
+		Balances: balances,
+		Locked:   []channel.SubAlloc{},
 	}
 	addr := ethwallet.Address(s.participant)
 	proposal, err := client.NewLedgerChannelProposal(16, &addr, initBals, peers)
@@ -187,23 +660,141 @@ func (s *ControlService) propose_channel() error {
 	if err != nil {
 		return err
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.agreements[ch.ID()] = proposal.FundingAgreement
 	s.registerChannel(ch)
+	if name != "" {
+		if _, taken := s.aliases[name]; taken {
+			return fmt.Errorf("Name %q was taken while proposing, use the index %d", name, len(s.channelsIds)-1)
+		}
+		s.aliases[name] = ch.ID()
+	}
 	return nil
 }
 
-type adjudicatorEventHandler struct {
-	channel *client.Channel
+// propose_sub_channel proposes a sub-channel of the ledger channel at the
+// parent index, in which we hold amount of the parent's first asset.
+func (s *ControlService) propose_sub_channel(parent_index int, amount *big.Int) error {
+	parent, err := s.get_channel(parent_index)
+	if err != nil {
+		return err
+	}
+	if !parent.IsLedgerChannel() {
+		return errors.New("Parent must be a ledger channel")
+	}
+	defer s.lockChannel(parent.ID())()
+
+	if parent.IsClosed() {
+		return errors.New("Parent channel is already closed")
+	}
+	assets := parent.State().Assets
+	balances := make([][]*big.Int, len(assets))
+	for i := range balances {
+		balances[i] = make([]*big.Int, len(parent.Params().Parts))
+		for j := range balances[i] {
+			balances[i][j] = new(big.Int)
+		}
+	}
+	balances[0][parent.Idx()].Set(amount)
+	initBals := &channel.Allocation{
+		Assets:   append([]channel.Asset(nil), assets...),
+		Balances: balances,
+		Locked:   []channel.SubAlloc{},
+	}
+	proposal, err := client.NewSubChannelProposal(parent.ID(), 16, initBals)
+	if err != nil {
+		return err
+	}
+	ch, err := s.client.ProposeChannel(context.Background(), proposal)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.agreements[ch.ID()] = proposal.FundingAgreement
+	s.registerChannel(ch)
+	return nil
 }
 
-func (h adjudicatorEventHandler) HandleAdjudicatorEvent(channel.AdjudicatorEvent) {
-	err := h.channel.Settle(context.Background(), false)
+// propose_virtual_channel proposes a virtual channel with Bob, in which we
+// hold amount of the first asset. It is funded from the ledger channel at the
+// parent index and Bob's ledger channel with the same intermediary. We cannot
+// see Bob's channel, so Bob is assumed to be its proposer (index 0), as is
+// the case for channels opened by propose.
+func (s *ControlService) propose_virtual_channel(parent_index int, peer_parent channel.ID, amount *big.Int) error {
+	parent, err := s.get_channel(parent_index)
+	if err != nil {
+		return err
+	}
+	if !parent.IsLedgerChannel() {
+		return errors.New("Parent must be a ledger channel")
+	}
+	defer s.lockChannel(parent.ID())()
+
+	if parent.IsClosed() {
+		return errors.New("Parent channel is already closed")
+	}
+	if len(parent.Params().Parts) != 2 {
+		return errors.New("Parent channel must have exactly two participants")
+	}
+	assets := parent.State().Assets
+	balances := make([][]*big.Int, len(assets))
+	for i := range balances {
+		balances[i] = []*big.Int{new(big.Int), new(big.Int)}
+	}
+	balances[0][0].Set(amount)
+	initBals := &channel.Allocation{
+		Assets:   append([]channel.Asset(nil), assets...),
+		Balances: balances,
+		Locked:   []channel.SubAlloc{},
+	}
+	// The index maps map our index (0) and Bob's index (1) in the virtual
+	// channel to the indices in the parent channels, where the intermediary
+	// stands in for the respective other party.
+	ourIdx := parent.Idx()
+	indexMaps := [][]channel.Index{
+		{ourIdx, 1 - ourIdx},
+		{1, 0},
+	}
+	peers := []wire.Address{s.perunID, simple.NewAddress("Bob")}
+	addr := ethwallet.Address(s.participant)
+	proposal, err := client.NewVirtualChannelProposal(16, &addr, initBals, peers,
+		[]channel.ID{parent.ID(), peer_parent}, indexMaps)
 	if err != nil {
-		panic(err)
+		return err
 	}
+	ch, err := s.client.ProposeChannel(context.Background(), proposal)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.agreements[ch.ID()] = proposal.FundingAgreement
+	s.registerChannel(ch)
+	return nil
+}
+
+type adjudicatorEventHandler struct {
+	channel *client.Channel
+	service *ControlService
+}
+
+func (h adjudicatorEventHandler) HandleAdjudicatorEvent(e channel.AdjudicatorEvent) {
+	h.service.publishEvent(h.channel.ID(), e)
+	h.service.advanceSettlement(h.channel.ID(), settlementEvent(e))
+
+	defer h.service.lockChannel(h.channel.ID())()
+	h.service.settleWithRetry(h.channel)
 }
 
-func (s *ControlService) dispatch_with_index_default_last(args []string, fn func(index int) error) error {
-	return s.dispatch_with_index(args, len(s.channelsIds)-1, fn)
+// dispatch_with_index_default_last parses the arguments `[<index>]`,
+// defaulting to the session's current channel or the last channel.
+func (s *ControlService) dispatch_with_index_default_last(sess *session, args []string, fn func(index int) error) error {
+	return s.dispatch_with_index(args, s.defaultIndex(sess), fn)
 }
 
 func (s *ControlService) dispatch_with_index(args []string, default_value int, fn func(index int) error) error {
@@ -211,73 +802,657 @@ func (s *ControlService) dispatch_with_index(args []string, default_value int, f
 	case 0:
 		return fn(default_value)
 	case 1:
-		index, err := strconv.Atoi(args[0])
+		index, err := s.resolveIndex(args[0])
 		if err != nil {
 			return err
 		}
 		return fn(index)
 	default:
-		return fmt.Errorf("Invalid argument count")
+		return usageErrorf("Invalid argument count")
+	}
+}
+
+// dispatch_with_index_and_amount parses the arguments `[<index>] [<amount>]`.
+// If a single argument is given, it is interpreted as the index if a default
+// amount is given and as the amount otherwise.
+func (s *ControlService) dispatch_with_index_and_amount(sess *session, args []string, default_amount *big.Int, fn func(index int, amount *big.Int) error) error {
+	index := s.defaultIndex(sess)
+	amount := default_amount
+	var err error
+	switch {
+	case len(args) == 2:
+		if index, err = s.resolveIndex(args[0]); err != nil {
+			return err
+		}
+		if amount, err = parseAmount(args[1]); err != nil {
+			return err
+		}
+	case len(args) == 1 && default_amount != nil:
+		if index, err = s.resolveIndex(args[0]); err != nil {
+			return err
+		}
+	case len(args) == 1:
+		if amount, err = parseAmount(args[0]); err != nil {
+			return err
+		}
+	case len(args) > 2:
+		return usageErrorf("Invalid argument count")
+	}
+	if amount == nil {
+		return usageErrorf("Missing amount")
+	}
+	return fn(index, amount)
+}
+
+// parseChannelID parses a hex encoded channel ID with optional 0x prefix.
+func parseChannelID(arg string) (channel.ID, error) {
+	var id channel.ID
+	data, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+	if err != nil {
+		return id, usageErrorf("Channel ID must be hex encoded, got %q", arg)
 	}
+	if len(data) != len(id) {
+		return id, usageErrorf("Channel ID must be %d bytes, got %d", len(id), len(data))
+	}
+	copy(id[:], data)
+	return id, nil
+}
+
+// parseAmount parses a non-negative integer amount (in Wei).
+func parseAmount(arg string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(arg, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, usageErrorf("Amount must be a non-negative integer, got %q", arg)
+	}
+	return amount, nil
+}
+
+// trackedIDs returns a snapshot of the tracked channel IDs.
+func (s *ControlService) trackedIDs() []channel.ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]channel.ID, len(s.channelsIds))
+	copy(ids, s.channelsIds)
+	return ids
+}
+
+// resolveIndex returns the index of the channel given by its index or name.
+func (s *ControlService) resolveIndex(arg string) (int, error) {
+	if index, err := strconv.Atoi(arg); err == nil {
+		if index < 0 {
+			return 0, usageErrorf("Index must be a non-negative integer, got %q", arg)
+		}
+		return index, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.aliases[arg]
+	if !ok {
+		return 0, usageErrorf("Index must be a non-negative integer or a channel name, got %q", arg)
+	}
+	for i, tracked := range s.channelsIds {
+		if tracked == id {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("Channel %q is not tracked", arg)
+}
+
+// checkAlias returns an error if the name cannot be given to a channel.
+func (s *ControlService) checkAlias(name string) error {
+	if _, err := strconv.Atoi(name); err == nil {
+		return errors.New("Channel names must not be numbers")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, taken := s.aliases[name]; taken {
+		return fmt.Errorf("Name %q is already taken", name)
+	}
+	return nil
 }
 
 func (s *ControlService) get_channel(index int) (*client.Channel, error) {
-	if index >= len(s.channelsIds) {
-		return nil, fmt.Errorf("Index out of bounds")
+	s.mu.Lock()
+	if index < 0 || index >= len(s.channelsIds) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("No channel at index %d, there are %d", index, len(s.channelsIds))
+	}
+	id := s.channelsIds[index]
+	s.mu.Unlock()
+
+	return s.client.Channel(id)
+}
+
+// lockChannel acquires the lock serializing the operations on the given
+// channel and returns the function releasing it.
+func (s *ControlService) lockChannel(id channel.ID) (unlock func()) {
+	s.mu.Lock()
+	l, ok := s.chLocks[id]
+	if !ok {
+		l = &sync.Mutex{}
+		s.chLocks[id] = l
+	}
+	s.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// requireChain returns the chain access or an error if it is not enabled.
+func (s *ControlService) requireChain() (*chainAccess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.chain == nil {
+		return nil, errors.New("Chain access is not enabled")
+	}
+	return s.chain, nil
+}
+
+// conclude closes the channel collaboratively: it proposes the final state,
+// which returns once the peer accepted it, and then settles the channel.
+func (s *ControlService) conclude(index int, w *bufio.Writer) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	defer s.lockChannel(ch.ID())()
+
+	report := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, format, args...)
+		w.Flush()
+	}
+	if ch.IsClosed() {
+		return errors.New("Channel is already closed")
+	}
+	if !ch.State().IsFinal {
+		report("Proposing final state at version %d\n", ch.State().Version+1)
+		err := ch.Update(context.Background(), func(state *channel.State) {
+			state.IsFinal = true
+		})
+		if err != nil {
+			return fmt.Errorf("Peer did not accept the final state: %w", err)
+		}
+		report("Peer accepted the final state\n")
 	}
-	return s.client.Channel(s.channelsIds[index])
+	report("Settling\n")
+	ctx, done := s.startOp("conclude", ch.ID())
+	defer done()
+	if err := s.settleLocked(ctx, ch); err != nil {
+		return fmt.Errorf("Settling: %w", err)
+	}
+	report("Channel concluded and withdrawn\n")
+	return nil
 }
 
-func (s *ControlService) force_close_channel(index int) error {
+// force_close_channel_at force closes the channel, registering the state with
+// the given version (nil for the latest). Older versions are taken from the
+// signed states retained by the recorder and registered through the
+// adjudicator; the channel is then settled by the adjudicator event handler.
+// The gas overrides apply to all transactions of the force-close.
+func (s *ControlService) force_close_channel_at(index int, version *uint64, gas GasOverrides) error {
 	ch, err := s.get_channel(index)
 	if err != nil {
 		return err
 	}
-	return ch.Settle(context.Background(), false)
+	defer s.lockChannel(ch.ID())()
+
+	if version == nil || *version == ch.State().Version {
+		ctx, done := s.startOp("force-close", ch.ID())
+		defer done()
+		return ch.Settle(WithGasOverrides(ctx, gas), false)
+	}
+
+	s.mu.Lock()
+	txs := s.txs
+	s.mu.Unlock()
+	if txs == nil {
+		return errors.New("Signed states are not recorded")
+	}
+	signed, ok := txs.At(ch.ID(), *version)
+	if !ok {
+		return fmt.Errorf("No signed state recorded for version %d, the latest version is %d", *version, ch.State().Version)
+	}
+	ctx, done := s.startOp("force-close", ch.ID())
+	defer done()
+	return s.register(WithGasOverrides(ctx, gas), ch, signed)
 }
 
-func (s *ControlService) update(index int, amount int64, is_final bool) error {
+func (s *ControlService) update(index int, amount *big.Int, is_final bool) error {
 	ch, err := s.get_channel(index)
 	if err != nil {
 		return err
 	}
+	defer s.lockChannel(ch.ID())()
+
 	return ch.Update(context.Background(), func(s *channel.State) {
 		part_idx := ch.Idx()
-		s.Balances[0][part_idx].Sub(s.Balances[0][part_idx], big.NewInt(amount))
-		s.Balances[0][1-part_idx].Add(s.Balances[0][1-part_idx], big.NewInt(amount))
+		s.Balances[0][part_idx].Sub(s.Balances[0][part_idx], amount)
+		s.Balances[0][1-part_idx].Add(s.Balances[0][1-part_idx], amount)
 		s.IsFinal = is_final
 	})
 }
 
+// transfer moves amount of the first asset from participant from to
+// participant to.
+func (s *ControlService) transfer(index int, from int, to int, amount *big.Int) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	defer s.lockChannel(ch.ID())()
+
+	parts := len(ch.Params().Parts)
+	if from < 0 || from >= parts || to < 0 || to >= parts {
+		return fmt.Errorf("Participant index out of bounds, the channel has %d participants", parts)
+	}
+	if from == to {
+		return errors.New("Source and destination participant are the same")
+	}
+	return ch.Update(context.Background(), func(s *channel.State) {
+		s.Balances[0][from].Sub(s.Balances[0][from], amount)
+		s.Balances[0][to].Add(s.Balances[0][to], amount)
+	})
+}
+
+// deposit sends an on-chain deposit for our participant into the asset holder
+// of the channel's first asset and waits for it to be confirmed. Updates
+// preserve the total of the balances, so deposits cannot add funds to an open
+// channel. Only the part of our share in the funding agreement which is not
+// deposited yet is accepted, anything beyond would be lost on settling.
+func (s *ControlService) deposit(index int, amount *big.Int) error {
+	chain, err := s.requireChain()
+	if err != nil {
+		return err
+	}
+	if amount.Sign() == 0 {
+		return errors.New("Amount must be positive")
+	}
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	defer s.lockChannel(ch.ID())()
+
+	if ch.IsClosed() {
+		return errors.New("Channel is already closed")
+	}
+	asset, ok := ch.State().Assets[0].(*ethchannel.Asset)
+	if !ok {
+		return fmt.Errorf("Unsupported asset type %T", ch.State().Assets[0])
+	}
+	s.mu.Lock()
+	agreement, ok := s.agreements[ch.ID()]
+	s.mu.Unlock()
+	if !ok {
+		return errors.New("No funding agreement recorded for this channel")
+	}
+
+	holdings, err := s.holdings(ch, asset)
+	if err != nil {
+		return err
+	}
+	missing := new(big.Int).Sub(agreement[0][ch.Idx()], holdings[ch.Idx()])
+	if missing.Sign() <= 0 {
+		return errors.New("Our share of the funding is deposited, further deposits would be lost on settling")
+	}
+	if amount.Cmp(missing) > 0 {
+		return fmt.Errorf("Only %v of our share of the funding is missing, further deposits would be lost on settling", missing)
+	}
+	depositor, err := s.depositorOf(asset)
+	if err != nil {
+		return err
+	}
+
+	fundingIDs := ethchannel.FundingIDs(ch.ID(), ch.Params().Parts...)
+	req := ethchannel.NewDepositReq(amount, chain.cb, *asset, chain.account, fundingIDs[ch.Idx()])
+	txs, err := depositor.Deposit(context.Background(), *req)
+	if err != nil {
+		return fmt.Errorf("Sending deposit: %w", err)
+	}
+	for _, tx := range txs {
+		if _, err := chain.cb.ConfirmTransaction(context.Background(), tx, chain.account); err != nil {
+			return fmt.Errorf("Confirming deposit: %w", err)
+		}
+	}
+	return nil
+}
+
+// holdings returns the on-chain deposits of each participant of the channel
+// in the asset holder of the asset.
+func (s *ControlService) holdings(ch *client.Channel, asset *ethchannel.Asset) ([]*big.Int, error) {
+	chain, err := s.requireChain()
+	if err != nil {
+		return nil, err
+	}
+	holder, err := assetholder.NewAssetholder(common.Address(asset.AssetHolder), chain.cb)
+	if err != nil {
+		return nil, err
+	}
+	fundingIDs := ethchannel.FundingIDs(ch.ID(), ch.Params().Parts...)
+	holdings := make([]*big.Int, len(fundingIDs))
+	for i, id := range fundingIDs {
+		holdings[i], err = holder.Holdings(&bind.CallOpts{Context: context.Background()}, id)
+		if err != nil {
+			return nil, fmt.Errorf("Reading holdings: %w", err)
+		}
+	}
+	return holdings, nil
+}
+
+// depositedTotal returns the sum of the on-chain deposits of all participants
+// for each asset of the channel, or "-" if it cannot be read.
+func (s *ControlService) depositedTotal(ch *client.Channel, assets []channel.Asset) string {
+	totals := make([]*big.Int, len(assets))
+	for i, asset := range assets {
+		ethAsset, ok := asset.(*ethchannel.Asset)
+		if !ok {
+			return "-"
+		}
+		holdings, err := s.holdings(ch, ethAsset)
+		if err != nil {
+			return "-"
+		}
+		totals[i] = new(big.Int)
+		for _, h := range holdings {
+			totals[i].Add(totals[i], h)
+		}
+	}
+	return fmt.Sprint(totals)
+}
+
+// printFundingAgreement prints the agreed funding next to the actual deposits
+// in the asset holders, marking participants which did not deposit their
+// share (yet).
+func (s *ControlService) printFundingAgreement(index int, w io.Writer) error {
+	if _, err := s.requireChain(); err != nil {
+		return err
+	}
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	agreement, ok := s.agreements[ch.ID()]
+	s.mu.Unlock()
+	if !ok {
+		return errors.New("No funding agreement recorded for this channel")
+	}
+
+	parts := ch.Params().Parts
+	fmt_str := "  %-4v %-42v %-12v %-12v %s\n"
+	for i, asset := range ch.State().Assets {
+		ethAsset, ok := asset.(*ethchannel.Asset)
+		if !ok {
+			return fmt.Errorf("Unsupported asset type %T", asset)
+		}
+		holdings, err := s.holdings(ch, ethAsset)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "asset %d (%v)\n", i, common.Address(ethAsset.AssetHolder).Hex())
+		fmt.Fprintf(w, fmt_str, "part", "address", "agreed", "deposited", "")
+		for j, part := range parts {
+			agreed := agreement[i][j]
+			missing := ""
+			if holdings[j].Cmp(agreed) < 0 {
+				missing = fmt.Sprintf("<missing %v>", new(big.Int).Sub(agreed, holdings[j]))
+			}
+			fmt.Fprintf(w, fmt_str, j, part, agreed, holdings[j], missing)
+		}
+	}
+	return nil
+}
+
 func (s *ControlService) printStatus(w io.Writer) {
-	fmt_str := "%-5v %-9v %-8v %-12s %-7v %v %s\n"
-	fmt.Fprintf(w, fmt_str, "open", "type", "part_idx", "phase", "version", "state", "")
+	fmt_str := "%-5v %-9v %-8v %-12s %-10v %-7v %-10v %v %s\n"
+	fmt.Fprintf(w, fmt_str, "open", "type", "part_idx", "phase", "settlement", "version", "deposited", "state", "")
 
-	for _, id := range s.channelsIds {
+	for _, id := range s.trackedIDs() {
 		ch, err := s.client.Channel(id)
 		if err != nil {
 			fmt.Fprintf(w, "<%v>", err)
 			continue
 		}
 
-		channelType := "<unknown>"
-		if ch.IsLedgerChannel() {
-			channelType = "Ledger"
-		} else if ch.IsSubChannel() {
-			channelType = "Subledger"
-		} else if ch.IsVirtualChannel() {
-			channelType = "Virtual"
-		}
-
 		phase := ch.Phase()
 		state := ch.State()
-		isFinal := ""
+		notes := ""
 		if state.IsFinal {
-			isFinal = "<final>"
+			notes = "<final>"
 		}
 
 		balances := state.Allocation.Balances
 
-		fmt.Fprintf(w, fmt_str, !ch.IsClosed(), channelType, ch.Idx(), phase.String(), state.Version, balances, isFinal)
+		deposited := s.depositedTotal(ch, state.Assets)
+
+		// Label each balance row with the asset it belongs to.
+		assetBals := make([]string, len(balances))
+		for i, bals := range balances {
+			assetBals[i] = fmt.Sprintf("%s:%v", s.assetName(state.Assets[i]), bals)
+		}
+
+		if err := s.channelError(id); err != nil {
+			notes = strings.TrimSpace(notes + " <error: " + err.Error() + ">")
+		}
+
+		fmt.Fprintf(w, fmt_str, !ch.IsClosed(), channelType(ch), ch.Idx(), phase.String(), s.settlementOf(id), state.Version, deposited, strings.Join(assetBals, " "), notes)
+	}
+}
+
+func (s *ControlService) peerCmd(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("Missing subcommand")
+	}
+	switch args[0] {
+	case "add":
+		if len(args) != 3 {
+			return usageErrorf("Invalid argument count")
+		}
+		if _, _, err := net.SplitHostPort(args[2]); err != nil {
+			return usageErrorf("Host must be given as host:port, got %q", args[2])
+		}
+		s.AddPeer(args[1], args[2])
+	case "list":
+		if len(args) != 1 {
+			return usageErrorf("Invalid argument count")
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		fmt_str := "%-16s %s\n"
+		fmt.Fprintf(w, fmt_str, "alias", "host")
+		for _, peer := range s.peers {
+			fmt.Fprintf(w, fmt_str, peer.alias, peer.host)
+		}
+	default:
+		return fmt.Errorf("Unknown subcommand %q", args[0])
+	}
+	return nil
+}
+
+// pingTimeout bounds the dial of the ping command.
+const pingTimeout = 5 * time.Second
+
+// pingPeer dials the registered host of the peer and reports the time it took
+// to establish the connection.
+func (s *ControlService) pingPeer(alias string, w io.Writer) error {
+	s.mu.Lock()
+	known := false
+	for _, peer := range s.peers {
+		known = known || peer.alias == alias
+	}
+	s.mu.Unlock()
+	if !known {
+		return fmt.Errorf("Unknown peer %q", alias)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := s.dialer.Dial(ctx, simple.NewAddress(alias), perunProto.Serializer())
+	if err != nil {
+		return fmt.Errorf("%s is unreachable: %w", alias, err)
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	fmt.Fprintf(w, "%s is reachable (%v)\n", alias, rtt.Round(time.Millisecond))
+	return nil
+}
+
+func (s *ControlService) printAddresses(w io.Writer) {
+	fmt_str := "%-24s %s\n"
+	fmt.Fprintf(w, fmt_str, "ethereum (checksummed)", s.participant.Hex())
+	fmt.Fprintf(w, fmt_str, "ethereum (raw hex)", hex.EncodeToString(s.participant.Bytes()))
+	fmt.Fprintf(w, fmt_str, "perun wallet address", ethwallet.AsWalletAddr(s.participant).String())
+
+	wireAddr := fmt.Sprint(s.perunID)
+	if data, err := s.perunID.MarshalBinary(); err == nil {
+		wireAddr = fmt.Sprintf("%s (hex: %s)", wireAddr, hex.EncodeToString(data))
+	}
+	fmt.Fprintf(w, fmt_str, "perun wire address", wireAddr)
+}
+
+// printAllParticipants prints the participants of all tracked channels.
+func (s *ControlService) printAllParticipants(w io.Writer) {
+	for index, id := range s.trackedIDs() {
+		ch, err := s.client.Channel(id)
+		if err != nil {
+			fmt.Fprintf(w, "%d: <%v>\n", index, err)
+			continue
+		}
+		fmt.Fprintf(w, "%d: 0x%x\n", index, id)
+		writeParticipants(w, ch)
+	}
+}
+
+// printParticipants prints the wallet and wire address of each participant
+// of the channel.
+func (s *ControlService) printParticipants(index int, w io.Writer) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	writeParticipants(w, ch)
+	return nil
+}
+
+func writeParticipants(w io.Writer, ch *client.Channel) {
+	fmt_str := "  %-4v %-42v %-12v %s\n"
+	fmt.Fprintf(w, fmt_str, "idx", "wallet", "wire", "")
+	peers := ch.Peers()
+	for i, part := range ch.Params().Parts {
+		wireAddr := "<unknown>"
+		if i < len(peers) {
+			wireAddr = fmt.Sprint(peers[i])
+		}
+		self := ""
+		if channel.Index(i) == ch.Idx() {
+			self = "<self>"
+		}
+		fmt.Fprintf(w, fmt_str, i, part, wireAddr, self)
+	}
+}
+
+// exportSignedState prints the latest signed state of the channel in the
+// encoding used for the state of a WatchRequestMsg.
+func (s *ControlService) exportSignedState(index int, w io.Writer) error {
+	s.mu.Lock()
+	txs := s.txs
+	s.mu.Unlock()
+	if txs == nil {
+		return errors.New("Signed states are not recorded")
+	}
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	signed, ok := txs.Latest(ch.ID())
+	if !ok {
+		return errors.New("No signed state recorded for this channel")
+	}
+	msg, err := perunProto.FromSignedState(&signed)
+	if err != nil {
+		return fmt.Errorf("Encoding signed state: %w", err)
+	}
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("Marshalling signed state: %w", err)
+	}
+	fmt.Fprintf(w, "version %d\n%s\n", signed.State.Version, base64.StdEncoding.EncodeToString(data))
+	return nil
+}
+
+func channelType(ch *client.Channel) string {
+	if ch.IsLedgerChannel() {
+		return "Ledger"
+	} else if ch.IsSubChannel() {
+		return "Subledger"
+	} else if ch.IsVirtualChannel() {
+		return "Virtual"
+	}
+	return "<unknown>"
+}
+
+// channelSummary is the machine-readable status of a channel.
+type channelSummary struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	PartIdx int    `json:"partIdx"`
+	Phase   string `json:"phase"`
+	// Settlement is the on-chain settlement progress, "-" if not settling.
+	Settlement string     `json:"settlement"`
+	Version    uint64     `json:"version"`
+	Assets     []string   `json:"assets"`
+	Balances   [][]string `json:"balances"` // Decimal strings, per asset and participant.
+	IsFinal    bool       `json:"isFinal"`
+	IsClosed   bool       `json:"isClosed"`
+	Error      string     `json:"error,omitempty"`
+}
+
+func (s *ControlService) printStatusJSON(w io.Writer) error {
+	ids := s.trackedIDs()
+	summaries := make([]channelSummary, 0, len(ids))
+	for _, id := range ids {
+		ch, err := s.client.Channel(id)
+		if err != nil {
+			summaries = append(summaries, channelSummary{
+				ID:    hex.EncodeToString(id[:]),
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		state := ch.State()
+		summary := channelSummary{
+			ID:         hex.EncodeToString(id[:]),
+			Type:       channelType(ch),
+			PartIdx:    int(ch.Idx()),
+			Phase:      ch.Phase().String(),
+			Settlement: s.settlementOf(id).String(),
+			Version:    state.Version,
+			Assets:     make([]string, len(state.Assets)),
+			Balances:   make([][]string, len(state.Balances)),
+			IsFinal:    state.IsFinal,
+			IsClosed:   ch.IsClosed(),
+		}
+		if err := s.channelError(id); err != nil {
+			summary.Error = err.Error()
+		}
+		for i, asset := range state.Assets {
+			summary.Assets[i] = s.assetName(asset)
+		}
+		for i, bals := range state.Balances {
+			summary.Balances[i] = make([]string, len(bals))
+			for j, bal := range bals {
+				summary.Balances[i][j] = bal.String()
+			}
+		}
+		summaries = append(summaries, summary)
 	}
+	return json.NewEncoder(w).Encode(summaries)
 }
@@ -0,0 +1,167 @@
+package control
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wire/net/simple"
+)
+
+func TestResync(t *testing.T) {
+	env := newTestEnv(t)
+	s := env.controlService()
+	env.alice.OnNewChannel(s.HandleNewChannel)
+	accepted := env.alice.handle(t)
+
+	// Opened by Bob, without the control service of Alice knowing.
+	acceptedChannel := func() *client.Channel {
+		env.openChannel(t, env.bob, env.alice, 1000, 1000)
+		select {
+		case ch := <-accepted:
+			return ch
+		case <-time.After(testTimeout):
+			t.Fatal("channel not accepted")
+			return nil
+		}
+	}
+	open, closed := acceptedChannel(), acceptedChannel()
+	if err := closed.Close(); err != nil {
+		t.Fatalf("closing channel: %v", err)
+	}
+
+	out, err := runCmd(t, s, "resync")
+	if err != nil {
+		t.Fatalf("resync: %v", err)
+	}
+	if !s.isTracked(open.ID()) || s.isTracked(closed.ID()) {
+		t.Fatalf("expected only the open channel to be tracked, output:\n%s", out)
+	}
+	env.alice.waitWatched(t, open)
+	if !strings.Contains(out, "1 channel(s) added") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	s.knownMu.Lock()
+	known := len(s.knownChannels)
+	s.knownMu.Unlock()
+	if known != 1 {
+		t.Errorf("%d channels known, want only the open one", known)
+	}
+
+	out, err = runCmd(t, s, "resync")
+	if err != nil {
+		t.Fatalf("second resync: %v", err)
+	}
+	if !strings.Contains(out, "0 channel(s) added") {
+		t.Errorf("channel added twice, output:\n%s", out)
+	}
+}
+
+func TestAddr(t *testing.T) {
+	participant := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	s := NewControlService(nil, simple.NewAddress("Alice"), nil, 1337, nil, participant, "")
+
+	out, err := runCmd(t, &s, "addr")
+	if err != nil {
+		t.Fatalf("addr: %v", err)
+	}
+	for _, want := range []string{
+		"ethereum (checksummed)   0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed\n",
+		"ethereum (raw hex)       5aaeb6053f3e94c9b9a09f33669435e7ef1beaed\n",
+		"perun wallet address     0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed\n",
+		"(hex: 416c696365)\n", // "Alice"
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	env := newTestEnv(t)
+	s := env.controlService()
+	env.enableChainAccess(t, s)
+	env.bob.handle(t)
+	ch := env.openChannel(t, env.alice, env.bob, 1000, 1000)
+	env.register(t, s, ch)
+
+	// Fully funded, any deposit would be lost.
+	s.RegisterFundingAgreement(ch.ID(), channel.Balances{{big.NewInt(1000), big.NewInt(1000)}})
+	if _, err := runCmd(t, s, "deposit 0 1"); err == nil || !strings.Contains(err.Error(), "is deposited") {
+		t.Fatalf("got error %v for a deposit into a funded channel", err)
+	}
+
+	// Pretend that the agreement was larger than what was funded.
+	s.RegisterFundingAgreement(ch.ID(), channel.Balances{{big.NewInt(1050), big.NewInt(1000)}})
+	if _, err := runCmd(t, s, "deposit 0 51"); err == nil || !strings.Contains(err.Error(), "Only 50") {
+		t.Fatalf("got error %v for a deposit exceeding the missing share", err)
+	}
+	if _, err := runCmd(t, s, "deposit 0 50"); err != nil {
+		t.Fatalf("depositing the missing share: %v", err)
+	}
+	holdings, err := s.holdings(ch, env.setup.Asset)
+	if err != nil {
+		t.Fatalf("reading holdings: %v", err)
+	}
+	if holdings[ch.Idx()].Cmp(big.NewInt(1050)) != 0 {
+		t.Errorf("holding %v, want 1050", holdings[ch.Idx()])
+	}
+
+	out, err := runCmd(t, s, "status")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !strings.Contains(out, "[2050]") {
+		t.Errorf("status does not show the on-chain deposits:\n%s", out)
+	}
+}
+
+func TestFundingAgreement(t *testing.T) {
+	env := newTestEnv(t)
+	s := env.controlService()
+	env.enableChainAccess(t, s)
+	env.bob.handle(t)
+	ch := env.openChannel(t, env.alice, env.bob, 1000, 500)
+	env.register(t, s, ch)
+
+	tests := []struct {
+		name        string
+		agreement   channel.Balances
+		wantMissing []string // Substrings of the participant rows, in order.
+	}{
+		{
+			name:        "funded",
+			agreement:   channel.Balances{{big.NewInt(1000), big.NewInt(500)}},
+			wantMissing: []string{"", ""},
+		},
+		{
+			name:        "underfunded",
+			agreement:   channel.Balances{{big.NewInt(1000), big.NewInt(800)}},
+			wantMissing: []string{"", "<missing 300>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.RegisterFundingAgreement(ch.ID(), tt.agreement)
+			out, err := runCmd(t, s, "funding-agreement")
+			if err != nil {
+				t.Fatalf("funding-agreement: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			// Asset and column headers, then one row per participant.
+			if len(lines) != 2+len(tt.wantMissing) {
+				t.Fatalf("unexpected output:\n%s", out)
+			}
+			for i, want := range tt.wantMissing {
+				row := lines[2+i]
+				if missing := strings.Contains(row, "<missing"); missing != (want != "") || !strings.Contains(row, want) {
+					t.Errorf("row of participant %d is %q, want it to contain %q", i, row, want)
+				}
+			}
+		})
+	}
+}
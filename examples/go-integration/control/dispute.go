@@ -0,0 +1,75 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wallet"
+)
+
+// disputeAccess holds what is needed to register states with the adjudicator
+// outside of the client, which only registers as part of settling.
+type disputeAccess struct {
+	adj    channel.Adjudicator
+	wallet wallet.Wallet
+}
+
+// EnableDisputes lets the control service register channel states with the
+// adjudicator, e.g. when a peer is unreachable. The wallet unlocks the
+// accounts of the channels. The signed states are taken from the recorder,
+// see RecordTransactions.
+func (s *ControlService) EnableDisputes(adj channel.Adjudicator, w wallet.Wallet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.disputes = &disputeAccess{adj: adj, wallet: w}
+}
+
+// registerLatest registers the latest signed state of the channel.
+func (s *ControlService) registerLatest(ctx context.Context, ch *client.Channel) error {
+	s.mu.Lock()
+	txs := s.txs
+	s.mu.Unlock()
+	if txs == nil {
+		return errors.New("Signed states are not recorded")
+	}
+	signed, ok := txs.Latest(ch.ID())
+	if !ok {
+		return errors.New("No signed state recorded for this channel")
+	}
+	return s.register(ctx, ch, signed)
+}
+
+// register registers the signed state of the channel with the adjudicator,
+// together with the latest states of its sub-channels.
+func (s *ControlService) register(ctx context.Context, ch *client.Channel, signed channel.SignedState) error {
+	s.mu.Lock()
+	disputes, txs := s.disputes, s.txs
+	s.mu.Unlock()
+	if disputes == nil {
+		return errors.New("Registering is not enabled")
+	}
+
+	acc, err := disputes.wallet.Unlock(ch.Params().Parts[ch.Idx()])
+	if err != nil {
+		return fmt.Errorf("Unlocking account: %w", err)
+	}
+	subs := make([]channel.SignedState, 0, len(signed.State.Locked))
+	for _, locked := range signed.State.Locked {
+		sub, ok := txs.Latest(locked.ID)
+		if !ok {
+			return fmt.Errorf("No signed state recorded for sub-channel 0x%x", locked.ID)
+		}
+		subs = append(subs, sub)
+	}
+	req := channel.AdjudicatorReq{
+		Params: signed.Params,
+		Acc:    acc,
+		Tx:     channel.Transaction{State: signed.State, Sigs: signed.Sigs},
+		Idx:    ch.Idx(),
+	}
+	return disputes.adj.Register(ctx, req, subs)
+}
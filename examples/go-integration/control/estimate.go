@@ -0,0 +1,92 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/perun-network/perun-eth-backend/bindings/adjudicator"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	"perun.network/go-perun/channel"
+)
+
+// adjudicatorABI is the parsed ABI of the adjudicator contract.
+var adjudicatorABI, _ = abi.JSON(strings.NewReader(adjudicator.AdjudicatorABI))
+
+// printEstimate estimates the gas of the transaction the latest state of the
+// channel would be put on-chain with and prints the fee at the current gas
+// price. Nothing is sent. Final states are concluded directly, other states
+// are registered. The withdrawal is not estimated, as it reverts before the
+// channel is concluded.
+func (s *ControlService) printEstimate(index int, w io.Writer) error {
+	chain, err := s.requireChain()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	txs := s.txs
+	s.mu.Unlock()
+	if txs == nil {
+		return errors.New("Signed states are not recorded")
+	}
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	signed, ok := txs.Latest(ch.ID())
+	if !ok {
+		return errors.New("No signed state recorded for this channel")
+	}
+
+	method, data, err := registerCalldata(signed)
+	if err != nil {
+		return fmt.Errorf("Encoding %s call: %w", method, err)
+	}
+	ctx := context.Background()
+	gas, err := chain.cb.EstimateGas(ctx, ethereum.CallMsg{
+		From: chain.account.Address,
+		To:   &chain.adjudicator,
+		Data: data,
+	})
+	if err != nil {
+		return fmt.Errorf("Estimating %s: %w", method, err)
+	}
+	price, err := chain.cb.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("Querying gas price: %w", err)
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), price)
+	gwei := new(big.Float).Quo(new(big.Float).SetInt(price), big.NewFloat(params.GWei))
+
+	fmt.Fprintf(w, "%s of version %d: %d gas at %s gwei = %v wei\n", method, signed.State.Version, gas, gwei.Text('f', 3), fee)
+	fmt.Fprintf(w, "withdrawal: not estimated, possible once the channel is concluded\n")
+	return nil
+}
+
+// registerCalldata returns the adjudicator method and calldata putting the
+// signed state on-chain.
+func registerCalldata(signed channel.SignedState) (string, []byte, error) {
+	sigs := make([][]byte, len(signed.Sigs))
+	for i, sig := range signed.Sigs {
+		sigs[i] = sig
+	}
+	ethParams := ethchannel.ToEthParams(signed.Params)
+	ethState := ethchannel.ToEthState(signed.State)
+
+	if signed.State.IsFinal {
+		data, err := adjudicatorABI.Pack("concludeFinal", ethParams, ethState, sigs)
+		return "concludeFinal", data, err
+	}
+	data, err := adjudicatorABI.Pack("register", adjudicator.AdjudicatorSignedState{
+		Params: ethParams,
+		State:  ethState,
+		Sigs:   sigs,
+	}, []adjudicator.AdjudicatorSignedState{})
+	return "register", data, err
+}
@@ -0,0 +1,95 @@
+package control
+
+import (
+	"bufio"
+	"fmt"
+
+	"perun.network/go-perun/channel"
+)
+
+// eventBufferSize is the number of events buffered per subscriber. Events are
+// dropped for subscribers which do not keep up, so that a slow control
+// connection never stalls the channel watcher.
+const eventBufferSize = 16
+
+func (s *ControlService) subscribeEvents(id channel.ID) chan channel.AdjudicatorEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := make(chan channel.AdjudicatorEvent, eventBufferSize)
+	s.eventSubs[id] = append(s.eventSubs[id], sub)
+	return sub
+}
+
+func (s *ControlService) unsubscribeEvents(id channel.ID, sub chan channel.AdjudicatorEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.eventSubs[id]
+	for i := range subs {
+		if subs[i] == sub {
+			s.eventSubs[id] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(s.eventSubs[id]) == 0 {
+		delete(s.eventSubs, id)
+	}
+	close(sub)
+}
+
+// publishEvent forwards an adjudicator event of the channel to all
+// subscribers.
+func (s *ControlService) publishEvent(id channel.ID, e channel.AdjudicatorEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.eventSubs[id] {
+		select {
+		case sub <- e:
+		default:
+		}
+	}
+}
+
+// watchEvents prints the adjudicator events of the channel as they arrive
+// until the next line is read from the connection.
+func (s *ControlService) watchEvents(index int, r *bufio.Scanner, w *bufio.Writer) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	// Written before the events, which are written by another goroutine.
+	fmt.Fprintf(w, "Watching channel 0x%x, press enter to stop\n", ch.ID())
+	w.Flush()
+
+	sub := s.subscribeEvents(ch.ID())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range sub {
+			fmt.Fprintln(w, formatEvent(e))
+			w.Flush()
+		}
+	}()
+	r.Scan()
+
+	s.unsubscribeEvents(ch.ID(), sub)
+	<-done
+	return nil
+}
+
+func formatEvent(e channel.AdjudicatorEvent) string {
+	var kind string
+	switch e.(type) {
+	case *channel.RegisteredEvent:
+		kind = "registered"
+	case *channel.ProgressedEvent:
+		kind = "progressed"
+	case *channel.ConcludedEvent:
+		kind = "concluded"
+	default:
+		kind = fmt.Sprintf("%T", e)
+	}
+	return fmt.Sprintf("%-10s version=%d timeout=%v", kind, e.Version(), e.Timeout())
+}
@@ -0,0 +1,62 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+
+	remote "go-integration/perun-remote"
+
+	"perun.network/go-perun/channel"
+)
+
+// UseFunder makes the fund command fund channels through the funder service,
+// the same way the remote server funds channels of its clients.
+func (s *ControlService) UseFunder(funder *remote.FunderService) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.funder = funder
+}
+
+// fund funds our share of the channel's current state through the funder
+// service, reporting the progress per asset. The recorded funding agreement
+// of the channel is used, or the balances of the state if there is none.
+func (s *ControlService) fund(index int, w *bufio.Writer) error {
+	s.mu.Lock()
+	funder := s.funder
+	s.mu.Unlock()
+	if funder == nil {
+		return errors.New("No funder service configured")
+	}
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	defer s.lockChannel(ch.ID())()
+
+	state := ch.State().Clone()
+	s.mu.Lock()
+	agreement, ok := s.agreements[ch.ID()]
+	s.mu.Unlock()
+	if !ok {
+		agreement = state.Balances
+	}
+
+	report := func(p remote.FundingProgress) {
+		fmt.Fprintf(w, "Asset %d funded (%d/%d)\n", p.Asset, p.Confirmed, p.Total)
+		w.Flush()
+	}
+	err = funder.Fund(context.Background(), channel.FundingReq{
+		Params:    ch.Params(),
+		State:     state,
+		Idx:       ch.Idx(),
+		Agreement: agreement,
+	}, report)
+	if err != nil {
+		return fmt.Errorf("Funding: %w", err)
+	}
+	fmt.Fprintf(w, "Channel funded\n")
+	return nil
+}
@@ -0,0 +1,77 @@
+package control
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GasOverrides replace the gas settings of the transactions sent for an
+// operation, e.g. to get a dispute mined promptly on a congested network.
+// Unset fields keep the settings chosen by the transactor.
+type GasOverrides struct {
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+	GasLimit  uint64
+}
+
+type gasOverridesKey struct{}
+
+// WithGasOverrides returns a context carrying the overrides to the
+// transactor, which applies them to the transactions sent with the context.
+func WithGasOverrides(ctx context.Context, o GasOverrides) context.Context {
+	return context.WithValue(ctx, gasOverridesKey{}, o)
+}
+
+// GasOverridesFrom returns the overrides carried by ctx, if any.
+func GasOverridesFrom(ctx context.Context) (GasOverrides, bool) {
+	o, ok := ctx.Value(gasOverridesKey{}).(GasOverrides)
+	return o, ok
+}
+
+// parseGasFlags removes the flags `--tip <gwei>`, `--fee <gwei>` and
+// `--gas <limit>` from the arguments and returns the remaining ones.
+func parseGasFlags(args []string) ([]string, GasOverrides, error) {
+	var (
+		rest []string
+		o    GasOverrides
+		err  error
+	)
+	for i := 0; i < len(args); i++ {
+		flag := args[i]
+		if flag != "--tip" && flag != "--fee" && flag != "--gas" {
+			rest = append(rest, flag)
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, o, usageErrorf("Missing value of %s", flag)
+		}
+		i++
+		switch flag {
+		case "--tip":
+			o.GasTipCap, err = parseGwei(args[i])
+		case "--fee":
+			o.GasFeeCap, err = parseGwei(args[i])
+		case "--gas":
+			o.GasLimit, err = strconv.ParseUint(args[i], 10, 64)
+		}
+		if err != nil {
+			return nil, o, usageErrorf("Value of %s must be a non-negative integer, got %q", flag, args[i])
+		}
+	}
+	if o.GasTipCap != nil && o.GasFeeCap != nil && o.GasFeeCap.Cmp(o.GasTipCap) < 0 {
+		return nil, o, usageErrorf("Fee cap %v below tip cap %v", o.GasFeeCap, o.GasTipCap)
+	}
+	return rest, o, nil
+}
+
+// parseGwei parses a non-negative integer amount of Gwei and returns it in Wei.
+func parseGwei(arg string) (*big.Int, error) {
+	gwei, err := parseAmount(arg)
+	if err != nil {
+		return nil, err
+	}
+	return gwei.Mul(gwei, big.NewInt(params.GWei)), nil
+}
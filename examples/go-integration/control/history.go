@@ -0,0 +1,67 @@
+package control
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"perun.network/go-perun/channel"
+)
+
+// historySize is the number of states retained per channel.
+const historySize = 32
+
+// stateHistory is a ring buffer of the latest states of a channel.
+type stateHistory struct {
+	states []*channel.State
+	next   int // Index of the oldest state once the buffer is full.
+}
+
+func (h *stateHistory) add(state *channel.State) {
+	if len(h.states) < historySize {
+		h.states = append(h.states, state)
+		return
+	}
+	h.states[h.next] = state
+	h.next = (h.next + 1) % historySize
+}
+
+// all returns the states from the oldest to the latest.
+func (h *stateHistory) all() []*channel.State {
+	return append(append([]*channel.State(nil), h.states[h.next:]...), h.states[:h.next]...)
+}
+
+// recordState adds a copy of the state to the history of its channel. The
+// caller must hold s.mu.
+func (s *ControlService) recordState(state *channel.State) {
+	h, ok := s.history[state.ID]
+	if !ok {
+		h = &stateHistory{}
+		s.history[state.ID] = h
+	}
+	h.add(state.Clone())
+}
+
+func (s *ControlService) printHistory(index int, w io.Writer) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	var states []*channel.State
+	if h, ok := s.history[ch.ID()]; ok {
+		states = h.all()
+	}
+	s.mu.Unlock()
+
+	fmt_str := "%-7v %-7v %s\n"
+	fmt.Fprintf(w, fmt_str, "version", "final", "balances")
+	for _, state := range states {
+		assetBals := make([]string, len(state.Balances))
+		for i, bals := range state.Balances {
+			assetBals[i] = fmt.Sprintf("%s:%v", s.assetName(state.Assets[i]), bals)
+		}
+		fmt.Fprintf(w, fmt_str, state.Version, state.IsFinal, strings.Join(assetBals, " "))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// operation is background or long-running work on a channel, listed by the
+// ops command.
+type operation struct {
+	id      int
+	name    string
+	channel channel.ID
+	started time.Time
+	// Cancels the context of the operation, nil if it cannot be canceled.
+	cancel context.CancelFunc
+}
+
+// startOp tracks a cancelable operation on the channel. The returned done
+// function has to be called when the operation ended.
+func (s *ControlService) startOp(name string, id channel.ID) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	opID := s.trackOp(name, id, cancel)
+	return ctx, func() {
+		cancel()
+		s.untrackOp(opID)
+	}
+}
+
+// trackOp tracks an operation, which cannot be canceled if cancel is nil,
+// and returns its ID.
+func (s *ControlService) trackOp(name string, id channel.ID, cancel context.CancelFunc) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextOp++
+	s.ops[s.nextOp] = &operation{
+		id:      s.nextOp,
+		name:    name,
+		channel: id,
+		started: time.Now(),
+		cancel:  cancel,
+	}
+	return s.nextOp
+}
+
+func (s *ControlService) untrackOp(opID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ops, opID)
+}
+
+// cancelOp cancels the context of the operation. The operation is listed
+// until it returned.
+func (s *ControlService) cancelOp(opID int) error {
+	s.mu.Lock()
+	op, ok := s.ops[opID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("Unknown operation %d", opID)
+	}
+	if op.cancel == nil {
+		return errors.New("Operation cannot be canceled")
+	}
+	op.cancel()
+	return nil
+}
+
+// printOps lists the running operations, oldest first.
+func (s *ControlService) printOps(w io.Writer) {
+	s.mu.Lock()
+	ops := make([]operation, 0, len(s.ops))
+	for _, op := range s.ops {
+		ops = append(ops, *op)
+	}
+	s.mu.Unlock()
+	sort.Slice(ops, func(i, j int) bool { return ops[i].id < ops[j].id })
+
+	fmt_str := "%-4v %-12v %-10v %-10v %v\n"
+	fmt.Fprintf(w, fmt_str, "id", "operation", "elapsed", "cancelable", "channel")
+	for _, op := range ops {
+		fmt.Fprintf(w, fmt_str, op.id, op.name, time.Since(op.started).Round(time.Second),
+			op.cancel != nil, fmt.Sprintf("0x%x", op.channel))
+	}
+}
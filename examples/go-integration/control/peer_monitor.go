@@ -0,0 +1,139 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wire"
+)
+
+// DisconnectReaction selects how the control service reacts when the peer of
+// an open channel is unreachable for longer than the grace period.
+type DisconnectReaction int
+
+const (
+	// ReactAlert only logs an alert.
+	ReactAlert DisconnectReaction = iota
+	// ReactDispute registers the latest state of the channel on-chain, so it
+	// can be settled without the peer.
+	ReactDispute
+)
+
+func (r DisconnectReaction) String() string {
+	switch r {
+	case ReactAlert:
+		return "alert"
+	case ReactDispute:
+		return "dispute"
+	default:
+		return fmt.Sprintf("DisconnectReaction(%d)", int(r))
+	}
+}
+
+// PeerMonitorConfig configures the peer monitoring of the control service.
+type PeerMonitorConfig struct {
+	Reaction DisconnectReaction
+	// How long a peer has to be disconnected before reacting.
+	GracePeriod time.Duration
+}
+
+// peerMonitor holds the grace timers of the disconnected peers.
+type peerMonitor struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer // See peerKey.
+}
+
+// MonitorPeers reacts as configured on the tracked open channels with a peer
+// whose connections the monitor reports as lost for longer than the grace
+// period. Reconnecting within the grace period cancels the reaction.
+func (s *ControlService) MonitorPeers(cfg PeerMonitorConfig, conns *ConnectionMonitor) {
+	m := &peerMonitor{timers: make(map[string]*time.Timer)}
+	conns.OnPeerChange(func(peer wire.Address, connected bool) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		key := peerKey(peer)
+		if timer, ok := m.timers[key]; ok {
+			timer.Stop()
+			delete(m.timers, key)
+		}
+		if connected {
+			return
+		}
+
+		log.Warnf("Lost connection to peer %v", peer)
+		var timer *time.Timer
+		timer = time.AfterFunc(cfg.GracePeriod, func() {
+			m.mu.Lock()
+			// A reconnect may have stopped the timer after it fired.
+			current := m.timers[key] == timer
+			if current {
+				delete(m.timers, key)
+			}
+			m.mu.Unlock()
+			if current {
+				s.peerUnreachable(cfg, peer)
+			}
+		})
+		m.timers[key] = timer
+	})
+}
+
+// peerUnreachable reacts on all tracked open channels with the peer.
+func (s *ControlService) peerUnreachable(cfg PeerMonitorConfig, peer wire.Address) {
+	for _, ch := range s.openChannels() {
+		for _, p := range ch.Peers() {
+			if p.Equal(peer) {
+				s.reactToDisconnect(context.Background(), cfg.Reaction, ch, peer, cfg.GracePeriod)
+				break
+			}
+		}
+	}
+}
+
+// reactToDisconnect holds the lock of the channel, so that the reaction does
+// not interleave with commands on the channel.
+func (s *ControlService) reactToDisconnect(ctx context.Context, reaction DisconnectReaction, ch *client.Channel, peer wire.Address, down time.Duration) {
+	defer s.lockChannel(ch.ID())()
+
+	if ch.IsClosed() {
+		return
+	}
+	log.Errorf("Peer %v of channel 0x%x unreachable for %v", peer, ch.ID(), down.Round(time.Second))
+	if reaction != ReactDispute {
+		return
+	}
+
+	log.Warnf("Registering latest state of channel 0x%x (version %d)", ch.ID(), ch.State().Version)
+	if err := s.registerLatest(ctx, ch); err != nil {
+		log.Errorf("Registering channel 0x%x: %v", ch.ID(), err)
+	}
+}
+
+// openChannels returns all tracked channels which are not closed.
+func (s *ControlService) openChannels() []*client.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var chs []*client.Channel
+	for _, id := range s.channelsIds {
+		ch, err := s.client.Channel(id)
+		if err != nil || ch.IsClosed() {
+			continue
+		}
+		chs = append(chs, ch)
+	}
+	return chs
+}
+
+func peerKey(peer wire.Address) string {
+	data, err := peer.MarshalBinary()
+	if err != nil {
+		return fmt.Sprint(peer)
+	}
+	return string(data)
+}
@@ -0,0 +1,158 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+)
+
+// fakeConn is a connection receiving the envelopes queued on recv.
+type fakeConn struct {
+	recv      chan *wire.Envelope
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *fakeConn) Recv() (*wire.Envelope, error) {
+	select {
+	case env := <-c.recv:
+		return env, nil
+	case <-c.closed:
+		return nil, errors.New("connection closed")
+	}
+}
+
+func (c *fakeConn) Send(*wire.Envelope) error { return nil }
+
+func (c *fakeConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+type dialerFunc func() wirenet.Conn
+
+func (d dialerFunc) Dial(context.Context, wire.Address, wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	return d(), nil
+}
+
+func (dialerFunc) Close() error { return nil }
+
+// connect opens a monitored connection from which the peer authenticated.
+func connect(t *testing.T, m *ConnectionMonitor, peer wire.Address) wirenet.Conn {
+	t.Helper()
+	fake := &fakeConn{recv: make(chan *wire.Envelope, 1), closed: make(chan struct{})}
+	conn, err := m.Dialer(dialerFunc(func() wirenet.Conn { return fake })).Dial(context.Background(), peer, nil)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	fake.recv <- &wire.Envelope{Sender: peer}
+	if _, err := conn.Recv(); err != nil {
+		t.Fatalf("receiving authentication: %v", err)
+	}
+	return conn
+}
+
+// registeringAdjudicator sends the versions it is asked to register.
+type registeringAdjudicator struct {
+	channel.Adjudicator
+	registered chan uint64
+}
+
+func (a *registeringAdjudicator) Register(_ context.Context, req channel.AdjudicatorReq, _ []channel.SignedState) error {
+	a.registered <- req.Tx.State.Version
+	return nil
+}
+
+func TestMonitorPeers(t *testing.T) {
+	const grace = 100 * time.Millisecond
+	env := newTestEnv(t)
+	env.bob.handle(t)
+	ch := env.openChannel(t, env.alice, env.bob, 1000, 1000)
+	s := env.controlService()
+	s.RecordTransactions(env.alice.txs)
+	env.register(t, s, ch)
+
+	tests := []struct {
+		name     string
+		reaction DisconnectReaction
+		// Disconnects Bob. The connections to him are opened before.
+		disconnect  func(m *ConnectionMonitor, conns []wirenet.Conn)
+		wantAlert   bool
+		wantDispute bool
+	}{
+		{
+			name:       "alert",
+			reaction:   ReactAlert,
+			disconnect: func(_ *ConnectionMonitor, conns []wirenet.Conn) { conns[0].Close(); conns[1].Close() },
+			wantAlert:  true,
+		},
+		{
+			name:        "dispute",
+			reaction:    ReactDispute,
+			disconnect:  func(_ *ConnectionMonitor, conns []wirenet.Conn) { conns[0].Close(); conns[1].Close() },
+			wantAlert:   true,
+			wantDispute: true,
+		},
+		{
+			name:       "second connection open",
+			reaction:   ReactDispute,
+			disconnect: func(_ *ConnectionMonitor, conns []wirenet.Conn) { conns[0].Close() },
+		},
+		{
+			name:     "reconnected within grace period",
+			reaction: ReactDispute,
+			disconnect: func(m *ConnectionMonitor, conns []wirenet.Conn) {
+				conns[0].Close()
+				conns[1].Close()
+				connect(t, m, env.bob.addr)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adj := &registeringAdjudicator{registered: make(chan uint64, 1)}
+			s.EnableDisputes(adj, env.alice.wallet)
+			monitor := NewConnectionMonitor()
+			s.MonitorPeers(PeerMonitorConfig{Reaction: tt.reaction, GracePeriod: grace}, monitor)
+			logs := logtest.NewGlobal()
+			defer logs.Reset()
+
+			conns := []wirenet.Conn{
+				connect(t, monitor, env.bob.addr),
+				connect(t, monitor, env.bob.addr),
+				connect(t, monitor, env.alice.addr), // Unrelated peer.
+			}
+			tt.disconnect(monitor, conns)
+
+			select {
+			case version := <-adj.registered:
+				if !tt.wantDispute {
+					t.Fatalf("registered version %d", version)
+				}
+				if version != ch.State().Version {
+					t.Errorf("registered version %d, want %d", version, ch.State().Version)
+				}
+			case <-time.After(3 * grace):
+				if tt.wantDispute {
+					t.Fatal("no dispute registered")
+				}
+			}
+
+			alerted := false
+			for _, entry := range logs.AllEntries() {
+				alerted = alerted || strings.Contains(entry.Message, "unreachable")
+			}
+			if alerted != tt.wantAlert {
+				t.Errorf("alerted: %t, want %t", alerted, tt.wantAlert)
+			}
+		})
+	}
+}
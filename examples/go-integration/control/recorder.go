@@ -0,0 +1,127 @@
+package control
+
+import (
+	"context"
+	"sync"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+)
+
+// TxRecorder wraps the watcher of the client and records the signed states of
+// every channel the client publishes to it, retaining the last historySize
+// versions. client.Channel only exposes the latest state without signatures.
+type TxRecorder struct {
+	watcher.Watcher
+
+	mu     sync.Mutex
+	params map[channel.ID]*channel.Params
+	txs    map[channel.ID][]channel.Transaction // From the oldest to the latest.
+}
+
+// NewTxRecorder wraps the watcher. Pass the recorder to client.New instead of
+// the watcher.
+func NewTxRecorder(w watcher.Watcher) *TxRecorder {
+	return &TxRecorder{
+		Watcher: w,
+		params:  make(map[channel.ID]*channel.Params),
+		txs:     make(map[channel.ID][]channel.Transaction),
+	}
+}
+
+// StartWatchingLedgerChannel implements watcher.Watcher.
+func (r *TxRecorder) StartWatchingLedgerChannel(ctx context.Context, signed channel.SignedState) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	pub, sub, err := r.Watcher.StartWatchingLedgerChannel(ctx, signed)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.record(signed)
+	return &recordingPub{StatesPub: pub, recorder: r}, sub, nil
+}
+
+// StartWatchingSubChannel implements watcher.Watcher.
+func (r *TxRecorder) StartWatchingSubChannel(ctx context.Context, parent channel.ID, signed channel.SignedState) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	pub, sub, err := r.Watcher.StartWatchingSubChannel(ctx, parent, signed)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.record(signed)
+	return &recordingPub{StatesPub: pub, recorder: r}, sub, nil
+}
+
+func (r *TxRecorder) record(signed channel.SignedState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.params[signed.State.ID] = signed.Params
+	r.add(channel.Transaction{State: signed.State, Sigs: signed.Sigs})
+}
+
+func (r *TxRecorder) publish(tx channel.Transaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add(tx)
+}
+
+// add appends the transaction to the ones of its channel, unless a newer
+// version is recorded already. The caller must hold r.mu.
+func (r *TxRecorder) add(tx channel.Transaction) {
+	txs := r.txs[tx.ID]
+	if n := len(txs); n > 0 {
+		switch latest := txs[n-1]; {
+		case latest.Version > tx.Version:
+			return
+		case latest.Version == tx.Version:
+			txs[n-1] = tx
+			return
+		}
+	}
+	txs = append(txs, tx)
+	if len(txs) > historySize {
+		txs = txs[1:]
+	}
+	r.txs[tx.ID] = txs
+}
+
+// Latest returns the latest signed state of the channel.
+func (r *TxRecorder) Latest(id channel.ID) (channel.SignedState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	txs := r.txs[id]
+	if len(txs) == 0 {
+		return channel.SignedState{}, false
+	}
+	return r.signedState(txs[len(txs)-1]), true
+}
+
+// At returns the signed state of the channel with the given version, if it is
+// among the retained versions.
+func (r *TxRecorder) At(id channel.ID, version uint64) (channel.SignedState, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tx := range r.txs[id] {
+		if tx.Version == version {
+			return r.signedState(tx), true
+		}
+	}
+	return channel.SignedState{}, false
+}
+
+// signedState combines the transaction with the params of its channel. The
+// caller must hold r.mu.
+func (r *TxRecorder) signedState(tx channel.Transaction) channel.SignedState {
+	return channel.SignedState{Params: r.params[tx.ID], State: tx.State, Sigs: tx.Sigs}
+}
+
+type recordingPub struct {
+	watcher.StatesPub
+	recorder *TxRecorder
+}
+
+func (p *recordingPub) Publish(ctx context.Context, tx channel.Transaction) error {
+	p.recorder.publish(tx)
+	return p.StatesPub.Publish(ctx, tx)
+}
@@ -0,0 +1,94 @@
+package control
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// sessionTTL is how long a session can be resumed after its last command.
+const sessionTTL = time.Hour
+
+// session is the state of a control connection which survives reconnects. A
+// client resumes it by sending `resume <token>` on a new connection.
+type session struct {
+	token string
+	// Channel used by commands if no index is given, the last tracked
+	// channel if unset.
+	current    channel.ID
+	hasCurrent bool
+	lastUsed   time.Time
+}
+
+// newSession creates a session with a fresh token and forgets expired ones.
+func (s *ControlService) newSession() (*session, error) {
+	var token [8]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return nil, fmt.Errorf("Generating session token: %w", err)
+	}
+	sess := &session{token: hex.EncodeToString(token[:]), lastUsed: time.Now()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token, old := range s.sessions {
+		if time.Since(old.lastUsed) > sessionTTL {
+			delete(s.sessions, token)
+		}
+	}
+	s.sessions[sess.token] = sess
+	return sess, nil
+}
+
+// resumeSession returns the session of the token if it has not expired.
+func (s *ControlService) resumeSession(token string) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok || time.Since(sess.lastUsed) > sessionTTL {
+		return nil, fmt.Errorf("Unknown or expired session")
+	}
+	sess.lastUsed = time.Now()
+	return sess, nil
+}
+
+// touch marks the session as used.
+func (s *ControlService) touch(sess *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess.lastUsed = time.Now()
+}
+
+// selectChannel makes the channel the default of the session's commands.
+func (s *ControlService) selectChannel(sess *session, index int) error {
+	ch, err := s.get_channel(index)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess.current = ch.ID()
+	sess.hasCurrent = true
+	return nil
+}
+
+// defaultIndex returns the index of the session's current channel, or the
+// last tracked channel if none is selected.
+func (s *ControlService) defaultIndex(sess *session) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sess.hasCurrent {
+		for i, id := range s.channelsIds {
+			if id == sess.current {
+				return i
+			}
+		}
+	}
+	return len(s.channelsIds) - 1
+}
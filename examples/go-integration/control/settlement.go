@@ -0,0 +1,158 @@
+package control
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+)
+
+const (
+	// settleAttempts is the number of times settling is attempted after an
+	// adjudicator event, before the channel is marked as failed.
+	settleAttempts = 5
+	// settleBackoff is the delay before the first retry, which doubles with
+	// every further retry.
+	settleBackoff = 2 * time.Second
+)
+
+// settlementState is the on-chain settlement progress of a channel. The states
+// are ordered; a channel only ever advances to a later state, except for
+// failed channels, which may still be settled later.
+type settlementState int
+
+const (
+	settlementNone settlementState = iota
+	settlementPending
+	settlementRegistered
+	settlementConcluded
+	settlementWithdrawn
+	// Settling failed repeatedly, the funds are still locked.
+	settlementFailed
+)
+
+func (st settlementState) String() string {
+	switch st {
+	case settlementNone:
+		return "-"
+	case settlementPending:
+		return "pending"
+	case settlementRegistered:
+		return "registered"
+	case settlementConcluded:
+		return "concluded"
+	case settlementWithdrawn:
+		return "withdrawn"
+	case settlementFailed:
+		return "FAILED"
+	}
+	return "<unknown>"
+}
+
+// advanceSettlement records that the channel reached the settlement state,
+// unless it already reached a later one.
+func (s *ControlService) advanceSettlement(id channel.ID, st settlementState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cur := s.settlement[id]; st > cur || cur == settlementFailed {
+		s.settlement[id] = st
+	}
+}
+
+// failSettlement records that settling the channel failed, unless it was
+// withdrawn in the meantime.
+func (s *ControlService) failSettlement(id channel.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settlement[id] != settlementWithdrawn {
+		s.settlement[id] = settlementFailed
+	}
+}
+
+// settleWithRetry settles the channel, retrying with exponential backoff if it
+// fails, e.g. because a transaction was underpriced or the node was briefly
+// unavailable. If all attempts fail, the channel is marked as failed in the
+// status instead of taking down the process.
+// The retries can be stopped with the cancel command. The error of the last
+// attempt is recorded as the channel's error.
+func (s *ControlService) settleWithRetry(ch *client.Channel) {
+	ctx, done := s.startOp("settle", ch.ID())
+	defer done()
+
+	backoff := settleBackoff
+	for attempt := 1; ; attempt++ {
+		err := s.settleLocked(ctx, ch)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			log.Warnf("Settling channel 0x%x canceled: %v", ch.ID(), err)
+			s.failSettlement(ch.ID())
+			s.recordChannelError(ch.ID(), fmt.Errorf("settling canceled: %w", err))
+			return
+		}
+		if attempt >= settleAttempts {
+			log.Errorf("Settling channel 0x%x failed %d times, giving up: %v", ch.ID(), attempt, err)
+			s.failSettlement(ch.ID())
+			s.recordChannelError(ch.ID(), fmt.Errorf("settling: %w", err))
+			return
+		}
+		log.Warnf("Settling channel 0x%x failed, retrying in %v: %v", ch.ID(), backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		backoff *= 2
+	}
+}
+
+// recordChannelError records the error of a background operation on the
+// channel, which is shown by the status commands instead of crashing the
+// process.
+func (s *ControlService) recordChannelError(id channel.ID, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.channelErrs[id] = err
+	s.channelErrCount++
+}
+
+// channelError returns the last recorded error of the channel, nil if none.
+func (s *ControlService) channelError(id channel.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.channelErrs[id]
+}
+
+// ChannelErrorCount returns the number of errors of background operations on
+// channels since the start, e.g. for metrics.
+func (s *ControlService) ChannelErrorCount() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.channelErrCount
+}
+
+func (s *ControlService) settlementOf(id channel.ID) settlementState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.settlement[id]
+}
+
+// settlementEvent returns the settlement state reached with the adjudicator
+// event.
+func settlementEvent(e channel.AdjudicatorEvent) settlementState {
+	switch e.(type) {
+	case *channel.RegisteredEvent, *channel.ProgressedEvent:
+		return settlementRegistered
+	case *channel.ConcludedEvent:
+		return settlementConcluded
+	}
+	return settlementNone
+}
@@ -0,0 +1,220 @@
+package control
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/perun-network/perun-eth-backend/bindings/assetholder"
+	ethctest "github.com/perun-network/perun-eth-backend/channel/test"
+	ethwtest "github.com/perun-network/perun-eth-backend/wallet/test"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+	"perun.network/go-perun/watcher/local"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/net/simple"
+)
+
+const (
+	testTimeout       = 20 * time.Second
+	testBlockInterval = 100 * time.Millisecond
+)
+
+// testEnv is a simulated chain with the clients Alice and Bob, which are
+// connected by a local bus. Alice is the party of the control service.
+type testEnv struct {
+	setup      *ethctest.Setup
+	alice, bob *testClient
+}
+
+type testClient struct {
+	*client.Client
+	addr    wire.Address
+	part    wallet.Account // Participant account in channels.
+	funding common.Address // Account paying the deposits.
+	wallet  wallet.Wallet
+	watcher *notifyingWatcher
+	txs     *TxRecorder
+}
+
+// notifyingWatcher sends the IDs of the ledger channels it starts watching.
+type notifyingWatcher struct {
+	watcher.Watcher
+	started chan channel.ID
+}
+
+func (w *notifyingWatcher) StartWatchingLedgerChannel(ctx context.Context, s channel.SignedState) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	pub, sub, err := w.Watcher.StartWatchingLedgerChannel(ctx, s)
+	w.started <- s.State.ID
+	return pub, sub, err
+}
+
+// waitWatched waits until ch is watched. Closing a channel while it is being
+// registered with the watcher makes go-perun's Channel.Watch panic.
+func (c *testClient) waitWatched(t *testing.T, ch *client.Channel) {
+	t.Helper()
+	for {
+		select {
+		case id := <-c.watcher.started:
+			if id != ch.ID() {
+				continue
+			}
+			// Watch holds the machine lock until it registered its close
+			// handler.
+			_ = ch.State()
+			return
+		case <-time.After(testTimeout):
+			t.Fatalf("channel 0x%x not watched", ch.ID())
+		}
+	}
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	setup := ethctest.NewSetup(t, rng, 2, testBlockInterval, 1)
+	bus := wire.NewLocalBus()
+
+	newClient := func(i int, name string) *testClient {
+		w := ethwtest.NewTmpWallet()
+		lw, err := local.NewWatcher(setup.Adjs[i])
+		if err != nil {
+			t.Fatalf("creating watcher: %v", err)
+		}
+		nw := &notifyingWatcher{Watcher: lw, started: make(chan channel.ID, 8)}
+		txs := NewTxRecorder(nw)
+		addr := simple.NewAddress(name)
+		c, err := client.New(addr, bus, setup.Funders[i], setup.Adjs[i], w, txs)
+		if err != nil {
+			t.Fatalf("creating client: %v", err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return &testClient{
+			Client:  c,
+			addr:    addr,
+			part:    w.NewRandomAccount(rng),
+			funding: setup.Accs[i].Account.Address,
+			wallet:  w,
+			watcher: nw,
+			txs:     txs,
+		}
+	}
+	return &testEnv{
+		setup: setup,
+		alice: newClient(0, "Alice"),
+		bob:   newClient(1, "Bob"),
+	}
+}
+
+// holder returns the address of the ETH asset holder.
+func (e *testEnv) holder() common.Address {
+	return common.Address(e.setup.Asset.AssetHolder)
+}
+
+// controlService returns a control service of Alice.
+func (e *testEnv) controlService() *ControlService {
+	chainID := e.setup.SimBackend.ChainID().Uint64()
+	holders := map[uint64]common.Address{chainID: e.holder()}
+	s := NewControlService(e.alice.Client, e.alice.addr, simple.NewTCPDialer(testTimeout), chainID, holders, e.alice.funding, "")
+	return &s
+}
+
+// handle lets c accept all proposals and updates. Accepted channels are sent
+// on the returned channel.
+func (c *testClient) handle(t *testing.T) <-chan *client.Channel {
+	t.Helper()
+	accepted := make(chan *client.Channel, 8)
+	go c.Handle(acceptingHandler{c: c, t: t, accepted: accepted}, acceptingHandler{})
+	return accepted
+}
+
+type acceptingHandler struct {
+	c        *testClient
+	t        *testing.T
+	accepted chan<- *client.Channel
+}
+
+func (h acceptingHandler) HandleProposal(p client.ChannelProposal, r *client.ProposalResponder) {
+	lp, ok := p.(*client.LedgerChannelProposalMsg)
+	if !ok {
+		h.t.Errorf("unexpected proposal %T", p)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	ch, err := r.Accept(ctx, lp.Accept(h.c.part.Address(), client.WithRandomNonce()))
+	if err != nil {
+		h.t.Errorf("accepting proposal: %v", err)
+		return
+	}
+	h.accepted <- ch
+}
+
+func (acceptingHandler) HandleUpdate(_ *channel.State, _ client.ChannelUpdate, r *client.UpdateResponder) {
+	_ = r.Accept(context.Background())
+}
+
+// openChannel opens a funded ledger channel proposed by the proposer to the
+// responder, whose proposals have to be handled. bals are the balances of the
+// proposer and the responder.
+func (e *testEnv) openChannel(t *testing.T, proposer, responder *testClient, bals ...int64) *client.Channel {
+	t.Helper()
+	alloc := channel.NewAllocation(2, e.setup.Asset)
+	alloc.SetAssetBalances(e.setup.Asset, []channel.Bal{big.NewInt(bals[0]), big.NewInt(bals[1])})
+	prop, err := client.NewLedgerChannelProposal(
+		60, proposer.part.Address(), alloc, []wire.Address{proposer.addr, responder.addr})
+	if err != nil {
+		t.Fatalf("creating proposal: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	ch, err := proposer.ProposeChannel(ctx, prop)
+	if err != nil {
+		t.Fatalf("proposing channel: %v", err)
+	}
+	return ch
+}
+
+// runCmd runs a control command in a fresh session and returns its output.
+func runCmd(t *testing.T, s *ControlService, cmd string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	err := s.processCmd(cmd, &session{}, bufio.NewScanner(strings.NewReader("")), w)
+	if flushErr := w.Flush(); flushErr != nil {
+		t.Fatalf("flushing output: %v", flushErr)
+	}
+	return buf.String(), err
+}
+
+// enableChainAccess lets s access the chain with Alice's funding account.
+func (e *testEnv) enableChainAccess(t *testing.T, s *ControlService) {
+	t.Helper()
+	cb := e.setup.Adjs[0].ContractBackend
+	holder, err := assetholder.NewAssetholder(e.holder(), cb)
+	if err != nil {
+		t.Fatalf("binding asset holder: %v", err)
+	}
+	adjudicator, err := holder.Adjudicator(&bind.CallOpts{})
+	if err != nil {
+		t.Fatalf("reading adjudicator address: %v", err)
+	}
+	s.EnableChainAccess(cb, e.setup.Accs[0].Account, adjudicator)
+}
+
+// register registers ch with the control service of Alice and waits until the
+// channel is watched.
+func (e *testEnv) register(t *testing.T, s *ControlService, ch *client.Channel) {
+	t.Helper()
+	s.RegisterChannel(ch)
+	e.alice.waitWatched(t, ch)
+}
@@ -0,0 +1,100 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+)
+
+// Shutdown settles all tracked open channels before the process exits. Each
+// channel is finalized cooperatively and settled; if the peer does not agree
+// to the final state, the latest state is registered on-chain instead, so
+// that it is protected in a dispute. Sub- and virtual channels are handled
+// before the ledger channels funding them. Shutdown returns once all channels
+// are handled or ctx is done, reporting the channels which failed.
+func (s *ControlService) Shutdown(ctx context.Context) error {
+	var ledger, other []*client.Channel
+	for _, id := range s.trackedIDs() {
+		ch, err := s.client.Channel(id)
+		if err != nil || ch.IsClosed() {
+			continue
+		}
+		if ch.IsLedgerChannel() {
+			ledger = append(ledger, ch)
+		} else {
+			other = append(other, ch)
+		}
+	}
+
+	var failed int
+	for _, chs := range [][]*client.Channel{other, ledger} {
+		failed += s.shutdownChannels(ctx, chs)
+	}
+	if failed > 0 {
+		return fmt.Errorf("Failed to settle %d channel(s)", failed)
+	}
+	return nil
+}
+
+// shutdownChannels concurrently settles the channels and returns the number
+// of channels which could not be settled.
+func (s *ControlService) shutdownChannels(ctx context.Context, chs []*client.Channel) int {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	for _, ch := range chs {
+		wg.Add(1)
+		go func(ch *client.Channel) {
+			defer wg.Done()
+			if err := s.shutdownChannel(ctx, ch); err != nil {
+				log.Errorf("Shutdown: channel 0x%x: %v", ch.ID(), err)
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(ch)
+	}
+	wg.Wait()
+	return failed
+}
+
+func (s *ControlService) shutdownChannel(ctx context.Context, ch *client.Channel) error {
+	defer s.lockChannel(ch.ID())()
+
+	if ch.IsClosed() {
+		return nil
+	}
+	if !ch.State().IsFinal {
+		err := ch.Update(ctx, func(state *channel.State) {
+			state.IsFinal = true
+		})
+		if err != nil {
+			log.Warnf("Shutdown: finalizing channel 0x%x failed, registering the latest state: %v", ch.ID(), err)
+			if err := s.registerLatest(ctx, ch); err != nil {
+				return fmt.Errorf("Registering: %w", err)
+			}
+			return nil
+		}
+	}
+	return s.settleLocked(ctx, ch)
+}
+
+// settleLocked settles the channel unless it is already withdrawn. The caller
+// must hold the lock of the channel.
+func (s *ControlService) settleLocked(ctx context.Context, ch *client.Channel) error {
+	if ch.Phase() == channel.Withdrawn {
+		return nil
+	}
+	s.advanceSettlement(ch.ID(), settlementPending)
+	if err := ch.Settle(ctx, false); err != nil {
+		return err
+	}
+	s.advanceSettlement(ch.ID(), settlementWithdrawn)
+	return nil
+}
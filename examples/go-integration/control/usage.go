@@ -0,0 +1,108 @@
+package control
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// helpColumn is the column at which the descriptions of the help start.
+const helpColumn = 27
+
+// command describes a form of a control command for the help and the usage
+// shown on malformed arguments.
+type command struct {
+	names string // Comma separated, e.g. "p, propose".
+	args  string
+	desc  string // Lines after the first are indented to the description.
+}
+
+var commands = []command{
+	{"h, help", "", "Print this message"},
+	{"q, quit", "", "Exit the control service (the go-side is still running afterwards)"},
+	{"resume", "<token>", "Continue the session of an earlier connection"},
+	{"use", "<index>", "Use the channel when no index is given (default: last channel)"},
+	{"p, propose", "[--name <name>] [<assets>]", "Propose a channel using the comma separated assets (default: eth, eth@<chain-id> on other chains)\n" +
+		"The name can be used instead of the index in other commands"},
+	{"ps, propose-sub", "[<parent-index>] <amount>", "Propose a sub-channel funded from a ledger channel"},
+	{"pv, propose-virtual", "<parent-index> <peer-parent-id> <amount>", "Propose a virtual channel with Bob over our and Bob's ledger channel"},
+	{"u, update", "[<index>] [<amount>]", "Update the current channel"},
+	{"u, update", "<index> <from-part> <to-part> <amount>", "Move the amount between the participants"},
+	{"c, close", "[<index>]", "Close the channel"},
+	{"close-all", "", "Close all open channels, force-closing if the peer does not agree"},
+	{"conclude", "[<index>]", "Close the channel collaboratively, reporting each step"},
+	{"estimate", "[<index>]", "Estimate the fee of putting the channel's state on-chain"},
+	{"f, force-close", "[<index>] [<version>] [--tip <gwei>] [--fee <gwei>] [--gas <limit>]", "\n" +
+		"Force close the channel, optionally registering an older retained version\n" +
+		"and overriding the gas tip cap, fee cap and limit of its transactions"},
+	{"d, deposit", "[<index>] <amount>", "Deposit the missing part of our funding share"},
+	{"fund", "[<index>]", "Fund our share of the channel through the funder service"},
+	{"funding-agreement", "[<index>]", "Compare the agreed funding with the on-chain deposits"},
+	{"history", "[<index>]", "Print the retained states of the channel"},
+	{"export", "[<index>]", "Print the signed state of the channel, protobuf encoded as base64"},
+	{"w, watch", "[<index>]", "Print the adjudicator events of the channel until the next input line"},
+	{"s, status", "[--json]", "Short status report on the channel"},
+	{"sj", "", "Status report as JSON (same as status --json)"},
+	{"r, resync", "", "Track channels known to the client but not to the control service"},
+	{"ops", "", "List the running operations, e.g. settling a channel"},
+	{"cancel", "<op-id>", "Cancel a running operation"},
+	{"peer", "add <alias> <host:port>", "Register the address of a peer"},
+	{"peer", "list", "List the registered peers"},
+	{"ping", "<alias>", "Check whether the peer is reachable"},
+	{"addr", "", "Show the addresses of this node in different encodings"},
+	{"addrs", "[<index>]", "Show the wallet and wire address of each participant (default: all channels)"},
+}
+
+// writeHelp writes the help of all commands.
+func writeHelp(w io.Writer) {
+	for _, c := range commands {
+		c.writeHelp(w)
+	}
+}
+
+func (c command) writeHelp(w io.Writer) {
+	line := "  " + c.names
+	if c.args != "" {
+		line += " " + c.args
+	}
+	indent := strings.Repeat(" ", helpColumn)
+	desc := strings.Split(c.desc, "\n")
+	// A description starting with a newline begins on the next line.
+	if len(line) < helpColumn {
+		line += indent[len(line):]
+	} else if desc[0] != "" {
+		line += " "
+	}
+	fmt.Fprintln(w, line+desc[0])
+	for _, l := range desc[1:] {
+		fmt.Fprintln(w, indent+l)
+	}
+}
+
+// usage returns the forms of the command with the given name, empty if there
+// is no such command.
+func usage(name string) string {
+	var forms []string
+	for _, c := range commands {
+		for _, n := range strings.Split(c.names, ", ") {
+			if n == name {
+				forms = append(forms, strings.TrimSpace(name+" "+c.args))
+			}
+		}
+	}
+	return strings.Join(forms, "\n       ")
+}
+
+// usageError is returned for malformed command arguments. It is followed by
+// the usage of the command.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, a ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
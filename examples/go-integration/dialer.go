@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"perun.network/go-perun/wire"
+	wirenet "perun.network/go-perun/wire/net"
+)
+
+// BackoffConfig configures the retries of a BackoffDialer.
+type BackoffConfig struct {
+	// Attempts is the maximum number of dial attempts, at least one.
+	Attempts int
+	// Initial is the delay after the first failed attempt, it doubles after
+	// each further failed attempt.
+	Initial time.Duration
+	// Max caps the delay between two attempts.
+	Max time.Duration
+}
+
+// DefaultBackoff retries for about two minutes before giving up.
+var DefaultBackoff = BackoffConfig{
+	Attempts: 8,
+	Initial:  time.Second,
+	Max:      30 * time.Second,
+}
+
+// BackoffDialer retries failed dials of the wrapped dialer with exponential
+// backoff, so that transient network failures do not sever the connection to
+// a peer. Only the error of the last attempt is returned.
+type BackoffDialer struct {
+	wirenet.Dialer
+	cfg BackoffConfig
+}
+
+func NewBackoffDialer(dialer wirenet.Dialer, cfg BackoffConfig) *BackoffDialer {
+	if cfg.Attempts < 1 {
+		cfg.Attempts = 1
+	}
+	return &BackoffDialer{Dialer: dialer, cfg: cfg}
+}
+
+// Dial dials the peer until it succeeds, the attempts are exhausted or ctx is
+// done.
+func (d *BackoffDialer) Dial(ctx context.Context, addr wire.Address, ser wire.EnvelopeSerializer) (wirenet.Conn, error) {
+	delay := d.cfg.Initial
+	for attempt := 1; ; attempt++ {
+		conn, err := d.Dialer.Dial(ctx, addr, ser)
+		if err == nil {
+			return conn, nil
+		}
+		if attempt >= d.cfg.Attempts {
+			return nil, fmt.Errorf("dialing %v failed after %d attempts: %w", addr, attempt, err)
+		}
+		logrus.Debugf("Dialing %v failed (attempt %d/%d), retrying in %v: %v",
+			addr, attempt, d.cfg.Attempts, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("dialing %v: %w (last error: %v)", addr, ctx.Err(), err)
+		}
+		if delay *= 2; delay > d.cfg.Max {
+			delay = d.cfg.Max
+		}
+	}
+}
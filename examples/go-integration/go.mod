@@ -6,8 +6,10 @@ replace perun.network/go-perun => ../../go-perun
 
 require (
 	github.com/ethereum/go-ethereum v1.10.26
+	github.com/google/uuid v1.2.0
 	github.com/perun-network/perun-eth-backend v0.1.1-0.20230106102129-5bbe33a7cb89
 	github.com/sirupsen/logrus v1.8.1
+	github.com/tyler-smith/go-bip39 v1.0.1-0.20181017060643-dbb3b84ba2ef
 	google.golang.org/protobuf v1.26.0
 	perun.network/go-perun v0.10.6
 	polycry.pt/poly-go v0.0.0-20220301085937-fb9d71b45a37
@@ -25,7 +27,6 @@ require (
 	github.com/go-ole/go-ole v1.2.1 // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/google/uuid v1.2.0 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/hashicorp/golang-lru v0.5.5-0.20210104140557-80c98217689d // indirect
 	github.com/holiman/bloomfilter/v2 v2.0.3 // indirect
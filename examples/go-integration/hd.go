@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// hdKey is an extended private key as defined by BIP-32.
+type hdKey struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+var errInvalidHDKey = errors.New("derived key is invalid")
+
+func newMasterKey(seed []byte) (*hdKey, error) {
+	return hdKeyFromHMAC([]byte("Bitcoin seed"), seed, nil)
+}
+
+// child derives the child key with the given index. Indices starting at
+// 0x80000000 are hardened.
+func (k *hdKey) child(index uint32) (*hdKey, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, math.PaddedBigBytes(k.key, 32)...)
+	} else {
+		sk, err := k.privateKey()
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&sk.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+	return hdKeyFromHMAC(k.chainCode, data, k.key)
+}
+
+func hdKeyFromHMAC(hmacKey, data []byte, parent *big.Int) (*hdKey, error) {
+	mac := hmac.New(sha512.New, hmacKey)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Cmp(n) >= 0 {
+		return nil, errInvalidHDKey
+	}
+	if parent != nil {
+		key.Add(key, parent).Mod(key, n)
+	}
+	if key.Sign() == 0 {
+		return nil, errInvalidHDKey
+	}
+	return &hdKey{key: key, chainCode: sum[32:]}, nil
+}
+
+func (k *hdKey) privateKey() (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(math.PaddedBigBytes(k.key, 32))
+}
+
+// deriveKey derives the private key at the given path from a BIP-39 seed.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	k, err := newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range path {
+		if k, err = k.child(index); err != nil {
+			return nil, err
+		}
+	}
+	return k.privateKey()
+}
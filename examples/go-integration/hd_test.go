@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// Test vector 1 of BIP-32.
+func TestHDKeyBIP32Vector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path      accounts.DerivationPath
+		key       string
+		chainCode string
+	}{
+		{
+			path:      accounts.DerivationPath{},
+			key:       "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			chainCode: "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+		},
+		{
+			path:      accounts.DerivationPath{0x80000000},
+			key:       "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			chainCode: "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+		},
+		{
+			path:      accounts.DerivationPath{0x80000000, 1},
+			key:       "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			chainCode: "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path.String(), func(t *testing.T) {
+			k, err := newMasterKey(seed)
+			if err != nil {
+				t.Fatalf("master key: %v", err)
+			}
+			for _, index := range tt.path {
+				if k, err = k.child(index); err != nil {
+					t.Fatalf("deriving child %d: %v", index, err)
+				}
+			}
+			if got := hex.EncodeToString(math.PaddedBigBytes(k.key, 32)); got != tt.key {
+				t.Errorf("key %s, want %s", got, tt.key)
+			}
+			if got := hex.EncodeToString(k.chainCode); got != tt.chainCode {
+				t.Errorf("chain code %s, want %s", got, tt.chainCode)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"encoding/binary"
+	"flag"
 	"fmt"
 	"go-integration/control"
 	remote "go-integration/perun-remote"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/params"
@@ -94,6 +97,25 @@ func setup_ganache(accounts ...accounts.Account) (ethchannel.ContractInterface,
 }
 
 func main() {
+	listen_cfg := DefaultListenConfig()
+	listen_cfg.RegisterFlags(flag.CommandLine)
+	gas_fee_cap := flag.String("gas-fee-cap", "", "EIP-1559 gas fee cap in Wei (requires -gas-tip-cap, default: chosen by go-ethereum)")
+	gas_tip_cap := flag.String("gas-tip-cap", "", "EIP-1559 gas tip cap in Wei (requires -gas-fee-cap)")
+	manage_nonces := flag.Bool("manage-nonces", false, "assign transaction nonces locally instead of using the pending nonce")
+	watch_store_dir := flag.String("watch-store", "", "directory persisting the channels watched by the remote watcher (disabled if empty)")
+	remote_idle_timeout := flag.Duration("remote-idle-timeout", remote.DefaultIdleTimeout, "time after which idle remote connections are closed (disabled if zero)")
+	remote_ping_interval := flag.Duration("remote-ping-interval", 0, "interval of keepalive pings on remote connections (disabled if zero)")
+	remote_pong_timeout := flag.Duration("remote-pong-timeout", remote.DefaultPongTimeout, "time a remote connection has to answer a keepalive ping before it is closed")
+	confirmations := flag.Uint64("confirmations", 1, "number of blocks a transaction has to be confirmed by (use more on chains with reorgs)")
+	contract_cache_file := flag.String("contract-cache", "", "file caching the deployed contract addresses per chain, reused while they have code (disabled if empty)")
+	deploy_token := flag.Bool("deploy-token", false, "deploy an ERC20 token (asset \"peru\") held by the funder and its asset holder")
+	watcher_reaction_delay := flag.Duration("watcher-reaction-delay", 0, "delay of the remote watcher's refutations, for testing dispute timeouts")
+	expected_chain_id := flag.Uint64("chain-id", 0, "chain ID the backend has to report, startup fails on a mismatch (0: accept any)")
+	eth_holders := make(ChainAddresses)
+	flag.Var(eth_holders, "eth-holder", "ETH asset holder on another chain as chain-id=address, usable in proposals as eth@<chain-id> (repeatable)")
+	shutdown_timeout := flag.Duration("shutdown-timeout", time.Minute, "time to settle the open channels on shutdown")
+	flag.Parse()
+
 	perunlogrus.Set(logrus.TraceLevel, &logrus.TextFormatter{})
 
 	w := NewSimpleWallet()
@@ -106,55 +128,113 @@ func main() {
 		"0x4bcebba3fc0cc4fdc2bfb6c10ac2cbf85367a75f2921a75bb76b9440616c87e4",
 		"0xec951c901d6b68a8e3b0faf34ef93d0e03d219efd6a0d996ebaf140632a465fd",
 	}
-	adjudicator_account := w.ImportFromSecretKeyHex(not_so_private_keys[0][2:])
-	deployer_account := w.ImportFromSecretKeyHex(not_so_private_keys[1][2:])
-	funder_account := w.ImportFromSecretKeyHex(not_so_private_keys[2][2:])
+	account_cfg := AccountConfig{
+		Adjudicator: w.ImportFromSecretKeyHex(not_so_private_keys[0][2:]),
+		Deployer:    w.ImportFromSecretKeyHex(not_so_private_keys[1][2:]),
+		Funder:      w.ImportFromSecretKeyHex(not_so_private_keys[2][2:]),
+	}
+	if err := account_cfg.Validate(w); err != nil {
+		panic(err)
+	}
 
-	contract_interface, chain_id := setup_blockchain(adjudicator_account, deployer_account, funder_account)
+	contract_interface, chain_id := setup_blockchain(account_cfg.Accounts()...)
+	// Assets, signatures and the control service all use the chain ID
+	// reported by the backend, which differs between ganache setups and the
+	// simulated backend.
+	if !chain_id.IsUint64() {
+		panic(fmt.Sprintf("chain ID %v out of range", chain_id))
+	}
+	if *expected_chain_id != 0 && chain_id.Uint64() != *expected_chain_id {
+		panic(fmt.Sprintf("backend reports chain ID %v, expected %d", chain_id, *expected_chain_id))
+	}
 
+	transactor := NewChainIdAwareTransactor(w, chain_id)
+	if *manage_nonces {
+		transactor.Nonces = NewNonceManager(contract_interface)
+	}
+	if *gas_fee_cap != "" || *gas_tip_cap != "" {
+		fee_cap, ok1 := new(big.Int).SetString(*gas_fee_cap, 10)
+		tip_cap, ok2 := new(big.Int).SetString(*gas_tip_cap, 10)
+		if !ok1 || !ok2 {
+			panic("invalid gas fee or tip cap")
+		}
+		transactor.Fees = StaticFees{GasFeeCap: fee_cap, GasTipCap: tip_cap}
+	}
+	if *confirmations == 0 {
+		panic("at least one confirmation is required")
+	}
+	// The contract backend waits for this many blocks before treating a
+	// transaction as final. It is used by the funder, the adjudicators and the
+	// control service alike. One block is fine for ganache, which never
+	// reorgs, but on a real chain a funding or withdrawal transaction can
+	// still be dropped by a reorg after its first block: the channel would be
+	// opened on deposits that vanish, or a dispute be considered handled
+	// while its registration is reverted. Use a depth covering the reorgs
+	// expected on the chain, e.g. 6 or more on mainnet-like chains.
 	cb := ethchannel.NewContractBackend(
 		contract_interface,
 		ethchannel.MakeChainID(chain_id),
-		NewChainIdAwareTransactor(w, chain_id),
-		1,
+		transactor,
+		*confirmations,
 	)
 
 	channel.RegisterDefaultApp(&payment.Resolver{})
 
-	// Deploy contracts
-	adjAddr, err := ethchannel.DeployAdjudicator(context.Background(), cb, deployer_account)
+	// Deploy contracts, reusing the ones of earlier runs if cached.
+	var contract_cache *ContractCache
+	if *contract_cache_file != "" {
+		var err error
+		if contract_cache, err = LoadContractCache(*contract_cache_file); err != nil {
+			panic(err)
+		}
+	}
+	adjAddr, err := contract_cache.Deploy(context.Background(), cb, chain_id, "adjudicator",
+		func() (common.Address, error) {
+			return ethchannel.DeployAdjudicator(context.Background(), cb, account_cfg.Deployer)
+		})
 	if err != nil {
 		panic(err)
 	}
-	eth_holder, err := ethchannel.DeployETHAssetholder(context.Background(), cb, adjAddr, deployer_account)
+	tokens := make(map[string]common.Address)
+	if *deploy_token {
+		token, err := contract_cache.Deploy(context.Background(), cb, chain_id, "token/peru",
+			func() (common.Address, error) {
+				return ethchannel.DeployPerunToken(context.Background(), cb, account_cfg.Deployer,
+					[]common.Address{account_cfg.Funder.Address}, new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil))
+			})
+		if err != nil {
+			panic(err)
+		}
+		tokens["peru"] = token
+	}
+	asset_holders, err := deploy_asset_holders(context.Background(), cb, contract_cache, adjAddr, chain_id, account_cfg.Deployer, tokens)
 	if err != nil {
 		panic(err)
 	}
+	eth_holder := common.Address(asset_holders[0].Asset.AssetHolder)
+	eth_holders[chain_id.Uint64()] = eth_holder
 
 	// Setup dependency injection objects
+	//
+	// The funder only funds on the chain of its contract backend, the asset
+	// holders on other chains are only usable in proposals.
 	funder := ethchannel.NewFunder(cb)
-	funder.RegisterAsset(
-		ethchannel.Asset{
-			ChainID: ethchannel.ChainID{
-				Int: chain_id,
-			},
-			AssetHolder: ethwallet.Address(eth_holder),
-		},
-		ethchannel.NewETHDepositor(),
-		funder_account,
-	)
+	for _, holder := range asset_holders {
+		funder.RegisterAsset(*holder.Asset, holder.Depositor, account_cfg.Funder)
+	}
 	adjudicator := ethchannel.NewAdjudicator(
 		cb,
 		adjAddr,
-		funder_account.Address,
-		adjudicator_account,
+		account_cfg.Receiver,
+		account_cfg.Adjudicator,
 	)
 	perunID := simple.NewAddress("Alice")
 	dialer := simple.NewTCPDialer(time.Minute)
-	dialer.Register(simple.NewAddress("Bob"), "192.168.1.126:1234")
+	conn_monitor := control.NewConnectionMonitor()
 	bus := wirenet.NewBus(
 		simple.NewAccount(perunID),
-		dialer,
+		// Peers are registered with the TCP dialer, the bus retries dials.
+		conn_monitor.Dialer(NewBackoffDialer(dialer, DefaultBackoff)),
 		protobuf.Serializer(),
 	)
 	wallet, err := phd.NewWallet(w, accounts.DefaultBaseDerivationPath.String(), 0)
@@ -165,7 +245,8 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	c, err := client.New(perunID, bus, funder, adjudicator, wallet, watcher_for_client)
+	tx_recorder := control.NewTxRecorder(watcher_for_client)
+	c, err := client.New(perunID, bus, funder, adjudicator, wallet, tx_recorder)
 	if err != nil {
 		panic(err)
 	}
@@ -174,58 +255,116 @@ func main() {
 		panic(err)
 	}
 
-	controlService := control.NewControlService(c, eth_holder, funder_account.Address)
+	controlService := control.NewControlService(c, perunID, dialer, chain_id.Uint64(), eth_holders, account_cfg.Receiver, listen_cfg.Control)
+	controlService.AddPeer("Bob", "192.168.1.126:1234")
+	for _, holder := range asset_holders {
+		controlService.RegisterAsset(holder.Name, holder.Asset, holder.Depositor)
+	}
+	if listen_cfg.ControlTokenFile != "" {
+		token, err := os.ReadFile(listen_cfg.ControlTokenFile)
+		if err != nil {
+			panic(err)
+		}
+		if len(strings.TrimSpace(string(token))) == 0 {
+			panic("empty control token")
+		}
+		controlService.RequireToken(strings.TrimSpace(string(token)))
+	}
+	c.OnNewChannel(controlService.HandleNewChannel)
+	controlService.EnableChainAccess(cb, account_cfg.Funder, adjAddr)
+	controlService.RecordTransactions(tx_recorder)
+	controlService.EnableDisputes(adjudicator, wallet)
 
 	var proposalHandler client.ProposalHandler = ProposalHandler{
 		addr:           bob_account.Address(),
 		controlService: &controlService,
+		// Restrict e.g. the accepted peers or balances here.
+		policy: ProposalPolicy{},
+	}
+	var updateHandler client.UpdateHandler = UpdateHandler{
+		// Set MinOwnBalance to protect against draining updates.
+		policy: UpdatePolicy{AllowFinal: true},
 	}
-	var updateHandler client.UpdateHandler = UpdateHandler{}
 
-	listener, err := simple.NewTCPListener(":1337")
+	listener, err := simple.NewTCPListener(listen_cfg.Bus)
 	if err != nil {
 		panic(err)
 	}
 
 	go c.Handle(proposalHandler, updateHandler)
-	go bus.Listen(listener)
+	go bus.Listen(conn_monitor.Listener(listener))
 
-	watcher_for_service, err := local.NewWatcher(adjudicator)
+	var watcher_adjudicator channel.Adjudicator = adjudicator
+	if *watcher_reaction_delay > 0 {
+		watcher_adjudicator = remote.NewDelayedAdjudicator(adjudicator, *watcher_reaction_delay)
+	}
+	watcher_for_service, err := local.NewWatcher(watcher_adjudicator)
 	if err != nil {
 		panic(err)
 	}
+	counters := &remote.Counters{}
+	watcher_opts := []remote.WatcherOption{
+		remote.WithReceiverAdjudicators(receiver_adjudicators(cb, adjAddr, account_cfg.Adjudicator)),
+		remote.WithMetrics(counters),
+	}
+	if *watch_store_dir != "" {
+		store, err := remote.NewFileWatchStore(*watch_store_dir)
+		if err != nil {
+			panic(err)
+		}
+		watcher_opts = append(watcher_opts, remote.WithWatchStore(store))
+	}
+	watcher_service := remote.NewWatcherService(watcher_for_service, adjudicator, watcher_opts...)
+	if err := watcher_service.RestoreWatching(); err != nil {
+		panic(err)
+	}
+	funder_service := remote.NewFunderService(funder,
+		remote.WithFundingObserver(remote.NewHoldingsObserver(cb, time.Second)),
+		remote.WithFunderMetrics(counters))
+	controlService.UseFunder(funder_service)
 	server, err := remote.NewServer(
-		remote.NewWatcherService(watcher_for_service, adjudicator),
-		remote.NewFunderService(funder), 1338)
+		watcher_service,
+		funder_service, listen_cfg.Remote,
+		remote.WithIdleTimeout(*remote_idle_timeout),
+		remote.WithKeepalive(*remote_ping_interval, *remote_pong_timeout))
 	if err != nil {
 		panic(err)
 	}
 	go server.Serve()
 	defer server.Close()
 
-	// Listener for giving the EthHolder address to Rust (only needed for example)
-	go func() {
-		// Listen for any connection attempt on port 1338 and send out some
-		// information like the ETH holder address. (needed for this example,
-		// we're assuming the application already knows these values (for now
-		// at least))
-		l, err := net.Listen("tcp", ":1339")
-		if err != nil {
+	if listen_cfg.Metrics != "" {
+		metrics_server := NewMetricsServer(counters, &controlService)
+		if err := metrics_server.ListenAndServe(listen_cfg.Metrics); err != nil {
 			panic(err)
 		}
+		defer metrics_server.Close()
+	}
+
+	// Listener for giving the EthHolder address to Rust (only needed for example)
+	//
+	// Listen for any connection attempt on the info address and send out some
+	// information like the ETH holder address. (needed for this example,
+	// we're assuming the application already knows these values (for now at
+	// least))
+	info_listener, err := net.Listen("tcp", listen_cfg.Info)
+	if err != nil {
+		panic(fmt.Errorf("info server: %w", err))
+	}
+	go func() {
+		l := info_listener
 		for {
 			conn, err := l.Accept()
 			if err != nil {
 				panic(err)
 			}
 
-			// Note that using two `conn.Write` calls here is not ideal, as it
-			// causes two separate 20-byte payload TCP segments.
-			_, err = conn.Write(eth_holder.Bytes())
-			if err != nil {
-				panic(err)
-			}
-			_, err = conn.Write(funder_account.Address.Bytes())
+			// Both addresses are sent as a single frame: the payload length
+			// as big-endian uint32, followed by the ETH holder and the
+			// receiver address.
+			payload := append(eth_holder.Bytes(), account_cfg.Receiver.Bytes()...)
+			frame := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+			_, err = conn.Write(append(frame, payload...))
 			if err != nil {
 				panic(err)
 			}
@@ -234,11 +373,17 @@ func main() {
 		}
 	}()
 
+	// React to peers becoming unreachable while channels with them are open.
+	controlService.MonitorPeers(control.PeerMonitorConfig{
+		Reaction:    control.ReactAlert,
+		GracePeriod: 2 * time.Minute,
+	}, conn_monitor)
+
 	// Control server
 	go func() {
 		err := controlService.Run()
 		if err != nil {
-			panic(err)
+			fmt.Printf("Control service stopped: %v\n", err)
 		}
 	}()
 
@@ -248,20 +393,43 @@ func main() {
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
 
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdown_timeout)
+	if err := controlService.Shutdown(ctx); err != nil {
+		fmt.Printf("Shutdown: %v\n", err)
+	}
+	cancel()
+
 	c.Close()
 	bus.Close()
 	println("Done")
 }
 
+// receiver_adjudicators creates adjudicators withdrawing to the receivers
+// requested by clients of the remote watcher service.
+func receiver_adjudicators(cb ethchannel.ContractBackend, adjAddr common.Address, acc accounts.Account) func(wallet.Address) (channel.Adjudicator, error) {
+	return func(receiver wallet.Address) (channel.Adjudicator, error) {
+		return ethchannel.NewAdjudicator(cb, adjAddr, ethwallet.AsEthAddr(receiver), acc), nil
+	}
+}
+
 type ProposalHandler struct {
 	addr           wallet.Address
 	controlService *control.ControlService
+	policy         ProposalPolicy
 }
 
 // HandleProposal implements client.ProposalHandler
 func (ph ProposalHandler) HandleProposal(proposal client.ChannelProposal, res *client.ProposalResponder) {
 	println("HandleProposal(): ", proposal, res)
 
+	if err := ph.policy.Check(proposal); err != nil {
+		fmt.Printf("Rejecting proposal: %v\n", err)
+		if err := res.Reject(context.Background(), err.Error()); err != nil {
+			fmt.Printf("Rejecting proposal failed: %v\n", err)
+		}
+		return
+	}
+
 	var nonce_share [32]byte
 	_, err := rand.Read(nonce_share[:])
 	if err != nil {
@@ -279,14 +447,25 @@ func (ph ProposalHandler) HandleProposal(proposal client.ChannelProposal, res *c
 	if err != nil {
 		panic(err)
 	}
+	ph.controlService.RegisterFundingAgreement(ch.ID(), proposal.Base().FundingAgreement)
 }
 
-type UpdateHandler struct{}
+type UpdateHandler struct {
+	policy UpdatePolicy
+}
 
 // HandleUpdate implements client.UpdateHandler
-func (UpdateHandler) HandleUpdate(state *channel.State, update client.ChannelUpdate, res *client.UpdateResponder) {
+func (uh UpdateHandler) HandleUpdate(state *channel.State, update client.ChannelUpdate, res *client.UpdateResponder) {
 	println("HandleUpdate(): ", state, res)
 
+	if err := uh.policy.Check(state, update); err != nil {
+		fmt.Printf("Rejecting update: %v\n", err)
+		if err := res.Reject(context.Background(), err.Error()); err != nil {
+			fmt.Printf("Rejecting update failed: %v\n", err)
+		}
+		return
+	}
+
 	err := res.Accept(context.Background())
 	if err != nil {
 		panic(err)
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+
+	"go-integration/control"
+	remote "go-integration/perun-remote"
+)
+
+// MetricsServer serves the metrics of the remote services and the control
+// service at /metrics in the Prometheus text format.
+type MetricsServer struct {
+	counters *remote.Counters
+	control  *control.ControlService
+	server   *http.Server
+}
+
+func NewMetricsServer(counters *remote.Counters, control *control.ControlService) *MetricsServer {
+	m := &MetricsServer{counters: counters, control: control}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	m.server = &http.Server{Handler: mux}
+	return m
+}
+
+// ListenAndServe serves the metrics at addr in the background.
+func (m *MetricsServer) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics server: %w", err)
+	}
+	go func() {
+		if err := m.server.Serve(listener); err != http.ErrServerClosed {
+			logrus.Errorf("Metrics server: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (m *MetricsServer) Close() error {
+	return m.server.Close()
+}
+
+func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	snapshot := m.counters.Snapshot()
+
+	var buf bytes.Buffer
+	metric := func(name, kind, help string, value interface{}) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+	}
+	metric("perun_goroutines", "gauge", "Number of goroutines.", runtime.NumGoroutine())
+	metric("perun_watched_channels", "gauge", "Number of channels watched by the remote watcher.", snapshot.Watched)
+	metric("perun_disputes_total", "counter", "Number of states registered on request of remote clients.", snapshot.Disputes)
+	metric("perun_withdrawals_total", "counter", "Number of successful withdrawals of the remote watcher.", snapshot.Withdrawals)
+	metric("perun_withdrawal_failures_total", "counter", "Number of failed withdrawals of the remote watcher.", snapshot.WithdrawalFailures)
+	metric("perun_funding_requests_total", "counter", "Number of successful funding requests of the remote funder.", snapshot.Fundings)
+	metric("perun_funding_failures_total", "counter", "Number of failed funding requests of the remote funder.", snapshot.FundingFailures)
+	metric("perun_control_commands_total", "counter", "Number of commands processed by the control service.", m.control.CommandCount())
+	metric("perun_control_channel_errors_total", "counter", "Number of errors of background operations on channels.", m.control.ChannelErrorCount())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		logrus.Debugf("Metrics server: writing response: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PendingNonceReader reads the pending nonce of an account, e.g. an
+// ethclient.Client.
+type PendingNonceReader interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces per account, so that concurrently
+// created transactions of the same account do not race for the same nonce.
+type NonceManager struct {
+	backend PendingNonceReader
+
+	mu   sync.Mutex
+	next map[common.Address]uint64
+}
+
+func NewNonceManager(backend PendingNonceReader) *NonceManager {
+	return &NonceManager{
+		backend: backend,
+		next:    make(map[common.Address]uint64),
+	}
+}
+
+// Next returns the nonce for the next transaction of the account. The first
+// nonce of an account is its pending nonce; if the pending nonce is ahead of
+// the tracked one, e.g. because of transactions sent elsewhere, it is used.
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending, err := m.backend.PendingNonceAt(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	nonce, ok := m.next[account]
+	if !ok || pending > nonce {
+		nonce = pending
+	}
+	m.next[account] = nonce + 1
+	return nonce, nil
+}
+
+// Reset forgets the tracked nonce of the account, so that the next nonce is
+// read from the chain again. It should be called if a transaction using a
+// nonce from Next was not sent.
+func (m *NonceManager) Reset(account common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.next, account)
+}
@@ -0,0 +1,88 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"perun.network/go-perun/channel"
+)
+
+// BatchWithdrawer is implemented by adjudicators that are able to withdraw
+// the funds of multiple channels in fewer on-chain transactions.
+type BatchWithdrawer interface {
+	WithdrawBatch(ctx context.Context, reqs []channel.AdjudicatorReq) error
+}
+
+type pendingWithdrawal struct {
+	req  channel.AdjudicatorReq
+	done chan error
+}
+
+// withdrawalBatcher collects withdrawal requests arriving within a time
+// window and submits them as a single batch. If the batch fails, the channels
+// are withdrawn individually, so that one failing channel does not keep the
+// others from being withdrawn.
+type withdrawalBatcher struct {
+	mu       sync.Mutex
+	window   time.Duration
+	clock    Clock
+	adj      channel.Adjudicator
+	batchAdj BatchWithdrawer
+	pending  []pendingWithdrawal
+}
+
+func newWithdrawalBatcher(adj channel.Adjudicator, batchAdj BatchWithdrawer, window time.Duration) *withdrawalBatcher {
+	return &withdrawalBatcher{adj: adj, batchAdj: batchAdj, window: window, clock: RealClock}
+}
+
+// Withdraw queues the request for the current batch and blocks until the
+// batch containing it was submitted.
+func (b *withdrawalBatcher) Withdraw(ctx context.Context, req channel.AdjudicatorReq) error {
+	done := make(chan error, 1)
+
+	b.mu.Lock()
+	b.pending = append(b.pending, pendingWithdrawal{req: req, done: done})
+	if len(b.pending) == 1 {
+		// First request of a new batch, the window starts now.
+		after := b.clock.After(b.window)
+		go func() {
+			<-after
+			b.flush()
+		}()
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *withdrawalBatcher) flush() {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	reqs := make([]channel.AdjudicatorReq, len(batch))
+	for i, p := range batch {
+		reqs[i] = p.req
+	}
+	err := b.batchAdj.WithdrawBatch(context.Background(), reqs)
+	if err == nil {
+		for _, p := range batch {
+			p.done <- nil
+		}
+		return
+	}
+
+	log.Warnf("Batch withdrawal of %d channel(s) failed, withdrawing individually: %v", len(batch), err)
+	for _, p := range batch {
+		p.done <- b.adj.Withdraw(context.Background(), p.req, nil)
+	}
+}
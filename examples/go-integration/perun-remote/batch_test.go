@@ -0,0 +1,117 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// countingAdjudicator counts the individual withdrawals. Other adjudicator
+// methods are not implemented.
+type countingAdjudicator struct {
+	channel.Adjudicator
+
+	mu        sync.Mutex
+	withdrawn int
+	batches   []int // Sizes of the batch withdrawals.
+}
+
+func (a *countingAdjudicator) Withdraw(context.Context, channel.AdjudicatorReq, channel.StateMap) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.withdrawn++
+	return nil
+}
+
+func (a *countingAdjudicator) counts() (withdrawn int, batches []int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.withdrawn, append([]int(nil), a.batches...)
+}
+
+// batchingAdjudicator additionally counts batch withdrawals, which fail with
+// err.
+type batchingAdjudicator struct {
+	countingAdjudicator
+	err error
+}
+
+func (a *batchingAdjudicator) WithdrawBatch(_ context.Context, reqs []channel.AdjudicatorReq) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.batches = append(a.batches, len(reqs))
+	return a.err
+}
+
+func TestWithdrawalBatching(t *testing.T) {
+	const channels = 3
+	errBatch := errors.New("batch failed")
+
+	tests := []struct {
+		name          string
+		adj           interface{ counts() (int, []int) }
+		window        time.Duration
+		wantWithdrawn int
+		wantBatches   []int
+	}{
+		{
+			name:        "batched",
+			adj:         &batchingAdjudicator{},
+			window:      100 * time.Millisecond,
+			wantBatches: []int{channels},
+		},
+		{
+			name:          "adjudicator without batching",
+			adj:           &countingAdjudicator{},
+			window:        100 * time.Millisecond,
+			wantWithdrawn: channels,
+		},
+		{
+			name:          "failed batch",
+			adj:           &batchingAdjudicator{err: errBatch},
+			window:        100 * time.Millisecond,
+			wantWithdrawn: channels,
+			wantBatches:   []int{channels},
+		},
+		{
+			name:          "zero window",
+			adj:           &batchingAdjudicator{},
+			wantWithdrawn: channels,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewWatcherService(nil, tt.adj.(channel.Adjudicator), WithWithdrawalBatchWindow(tt.window))
+
+			var wg sync.WaitGroup
+			errs := make(chan error, channels)
+			for i := 0; i < channels; i++ {
+				wg.Add(1)
+				go func(idx channel.Index) {
+					defer wg.Done()
+					errs <- service.withdraw(context.Background(), channel.AdjudicatorReq{Idx: idx}, nil, nil)
+				}(channel.Index(i))
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("withdrawing: %v", err)
+				}
+			}
+
+			withdrawn, batches := tt.adj.counts()
+			if withdrawn != tt.wantWithdrawn {
+				t.Errorf("%d individual withdrawals, want %d", withdrawn, tt.wantWithdrawn)
+			}
+			if fmt.Sprint(batches) != fmt.Sprint(tt.wantBatches) {
+				t.Errorf("batch sizes %v, want %v", batches, tt.wantBatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	stdsync "sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"go-integration/perun-remote/codec"
+	"go-integration/perun-remote/proto"
+)
+
+// ResponseError is returned by the Client for requests the server answered
+// with a failure. It unwraps to the matching error of this package, e.g.
+// ErrUnknownChannel, so callers can use errors.Is like on the server side.
+type ResponseError struct {
+	Code proto.ErrorCode
+	Msg  string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s (%v)", e.Msg, e.Code)
+}
+
+func (e *ResponseError) Unwrap() error {
+	switch e.Code {
+	case proto.ErrorCode_INVALID_REQUEST:
+		return ErrInvalidRequest
+	case proto.ErrorCode_UNKNOWN_CHANNEL:
+		return ErrUnknownChannel
+	case proto.ErrorCode_OUTDATED_VERSION:
+		return ErrOutdatedVersion
+	case proto.ErrorCode_FUNDING_TIMEOUT:
+		return ErrFundingTimeout
+	default:
+		return nil
+	}
+}
+
+// responseError returns the error of a response, nil on success.
+func responseError(success bool, msg string, code proto.ErrorCode) error {
+	if success {
+		return nil
+	}
+	return &ResponseError{Code: code, Msg: msg}
+}
+
+// Client speaks the remote protocol to a Server. Requests are synchronous and
+// may be sent concurrently. Responses are matched to the requests by type,
+// channel ID and, for watch requests, version. Failure responses to invalid
+// requests carry no channel ID, they are matched to the oldest request
+// awaiting a response of their type.
+type Client struct {
+	conn  net.Conn
+	codec codec.Codec
+
+	writeMu stdsync.Mutex
+
+	mu        stdsync.Mutex
+	pending   []*pendingRequest
+	onDispute func(*proto.DisputeNotification)
+	readErr   error         // set before closed is closed
+	closed    chan struct{} // closed when the connection ended
+}
+
+type pendingRequest struct {
+	match func(*proto.Message) bool
+	// Matches messages belonging to the request before the response, e.g.
+	// funding progress, which are passed to onProgress. Nil if there are none.
+	progress   func(*proto.Message) bool
+	onProgress func(*proto.Message)
+	resp       chan *proto.Message
+}
+
+// ClientOption configures optional behavior of a Client.
+type ClientOption func(*Client)
+
+// WithDisputeHandler calls handle for every dispute notification of the
+// server. It is called from the receiving goroutine and must not block.
+func WithDisputeHandler(handle func(*proto.DisputeNotification)) ClientOption {
+	return func(c *Client) {
+		c.onDispute = handle
+	}
+}
+
+// WithClientMaxFrameSize sets the maximum size of received messages.
+func WithClientMaxFrameSize(size uint32) ClientOption {
+	return func(c *Client) {
+		c.codec.MaxFrameSize = size
+	}
+}
+
+// DialClient connects to the server at addr and performs the handshake.
+func DialClient(ctx context.Context, addr string, opts ...ClientOption) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing server: %w", err)
+	}
+	c, err := NewClient(ctx, conn, opts...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient performs the handshake on the connection and returns a client
+// using it.
+func NewClient(ctx context.Context, conn net.Conn, opts ...ClientOption) (*Client, error) {
+	c := &Client{
+		conn:   conn,
+		codec:  codec.Codec{MaxFrameSize: DefaultMaxFrameSize},
+		closed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go c.receive()
+
+	resp, err := c.request(ctx,
+		&proto.Message{Msg: &proto.Message_HandshakeRequest{
+			HandshakeRequest: &proto.HandshakeRequestMsg{Version: ProtocolVersion}}},
+		func(m *proto.Message) bool { return m.GetHandshakeResponse() != nil },
+		nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	if hs := resp.GetHandshakeResponse(); !hs.Success {
+		return nil, fmt.Errorf("handshake rejected: %s", hs.Error)
+	}
+	return c, nil
+}
+
+// Close closes the connection. Requests in flight fail.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Watch asks the server to watch the channel with the state of the request.
+func (c *Client) Watch(ctx context.Context, req *proto.WatchRequestMsg) (*proto.WatchResponseMsg, error) {
+	state := req.GetState().GetState()
+	return c.watch(ctx, &proto.Message{Msg: &proto.Message_WatchRequest{WatchRequest: req}},
+		state.GetId(), state.GetVersion())
+}
+
+// Update updates the state of a watched channel.
+func (c *Client) Update(ctx context.Context, upd *proto.WatchUpdateMsg) (*proto.WatchResponseMsg, error) {
+	state := upd.GetRequest().GetState().GetState()
+	return c.watch(ctx, &proto.Message{Msg: &proto.Message_WatchUpdate{WatchUpdate: upd}},
+		state.GetId(), state.GetVersion())
+}
+
+func (c *Client) watch(ctx context.Context, msg *proto.Message, id []byte, version uint64) (*proto.WatchResponseMsg, error) {
+	resp, err := c.request(ctx, msg, func(m *proto.Message) bool {
+		wr := m.GetWatchResponse()
+		return wr != nil && (len(wr.ChannelId) == 0 ||
+			bytes.Equal(wr.ChannelId, id) && wr.Version == version)
+	}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	wr := resp.GetWatchResponse()
+	return wr, responseError(wr.Success, wr.Error, wr.Code)
+}
+
+// Fund asks the server to fund the channel. If onProgress is not nil, it is
+// called from the receiving goroutine for the progress reports of the server.
+func (c *Client) Fund(ctx context.Context, req *proto.FundingRequestMsg, onProgress func(*proto.FundingProgressMsg)) (*proto.FundingResponseMsg, error) {
+	id := req.GetInitialState().GetId()
+	resp, err := c.request(ctx, &proto.Message{Msg: &proto.Message_FundingRequest{FundingRequest: req}},
+		func(m *proto.Message) bool {
+			fr := m.GetFundingResponse()
+			return fr != nil && (len(fr.ChannelId) == 0 || bytes.Equal(fr.ChannelId, id))
+		},
+		func(m *proto.Message) bool {
+			p := m.GetFundingProgress()
+			return p != nil && bytes.Equal(p.ChannelId, id)
+		},
+		func(m *proto.Message) {
+			if onProgress != nil {
+				onProgress(m.GetFundingProgress())
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	fr := resp.GetFundingResponse()
+	return fr, responseError(fr.Success, fr.Error, fr.Code)
+}
+
+// ForceClose asks the server to register the latest state of the channel
+// on-chain.
+func (c *Client) ForceClose(ctx context.Context, req *proto.ForceCloseRequestMsg) (*proto.ForceCloseResponseMsg, error) {
+	resp, err := c.request(ctx, &proto.Message{Msg: &proto.Message_ForceCloseRequest{ForceCloseRequest: req}},
+		func(m *proto.Message) bool {
+			fr := m.GetForceCloseResponse()
+			return fr != nil && (len(fr.ChannelId) == 0 || bytes.Equal(fr.ChannelId, req.ChannelId))
+		}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	fr := resp.GetForceCloseResponse()
+	return fr, responseError(fr.Success, fr.Error, fr.Code)
+}
+
+// request sends the message and waits for the response matching match.
+// Messages matching progress are passed to onProgress until then.
+func (c *Client) request(
+	ctx context.Context,
+	msg *proto.Message,
+	match func(*proto.Message) bool,
+	progress func(*proto.Message) bool,
+	onProgress func(*proto.Message),
+) (*proto.Message, error) {
+	req := &pendingRequest{
+		match:      match,
+		progress:   progress,
+		onProgress: onProgress,
+		resp:       make(chan *proto.Message, 1),
+	}
+	c.mu.Lock()
+	c.pending = append(c.pending, req)
+	c.mu.Unlock()
+	defer c.removePending(req)
+
+	if err := c.send(msg); err != nil {
+		return nil, err
+	}
+	select {
+	case resp := <-req.resp:
+		return resp, nil
+	case <-c.closed:
+		return nil, fmt.Errorf("connection closed: %w", c.readErr)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) removePending(req *pendingRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, p := range c.pending {
+		if p == req {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Client) send(msg *proto.Message) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.codec.WriteMessage(c.conn, msg)
+}
+
+func (c *Client) receive() {
+	defer close(c.closed)
+	for {
+		msg, err := c.codec.ReadMessage(c.conn)
+		if err != nil {
+			c.readErr = err
+			return
+		}
+		switch msg := msg.GetMsg().(type) {
+		case *proto.Message_Ping:
+			if err := c.send(&proto.Message{Msg: &proto.Message_Pong{
+				Pong: &proto.PongMsg{Nonce: msg.Ping.Nonce}}}); err != nil {
+				log.Errorf("Client: sending pong: %v", err)
+			}
+			continue
+		case *proto.Message_Pong:
+			continue
+		case *proto.Message_DisputeNotification:
+			if c.onDispute != nil {
+				c.onDispute(msg.DisputeNotification)
+			}
+			continue
+		}
+		if !c.deliver(msg) {
+			log.Warnf("Client: dropping unexpected message %T", msg.GetMsg())
+		}
+	}
+}
+
+// deliver passes the message to the pending request it belongs to and returns
+// whether there is one. Responses without channel ID match every request of
+// their type, so they go to the oldest one, which is first in the list.
+func (c *Client) deliver(msg *proto.Message) bool {
+	c.mu.Lock()
+	for i, p := range c.pending {
+		if p.progress != nil && p.progress(msg) {
+			c.mu.Unlock()
+			p.onProgress(msg)
+			return true
+		}
+		if p.match(msg) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			c.mu.Unlock()
+			p.resp <- msg
+			return true
+		}
+	}
+	c.mu.Unlock()
+	return false
+}
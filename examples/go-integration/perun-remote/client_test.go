@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"testing"
+	"time"
+
+	ethwtest "github.com/perun-network/perun-eth-backend/wallet/test"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+
+	"go-integration/perun-remote/proto"
+)
+
+// testClient returns a client connected to a server over an in-memory
+// connection.
+func testClient(t *testing.T, funder channel.Funder) *Client {
+	t.Helper()
+	watcher := NewWatcherService(newStubWatcher(), registeringAdjudicator{})
+	server, err := NewConnServer(watcher, NewFunderService(funder, WithFundingTimeout(time.Minute)))
+	if err != nil {
+		t.Fatalf("creating server: %v", err)
+	}
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	client, err := NewClient(ctx, clientConn)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	var funded []channel.FundingReq
+	client := testClient(t, funderFunc(func(_ context.Context, req channel.FundingReq) error {
+		funded = append(funded, req)
+		return nil
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rng := rand.New(rand.NewSource(1))
+	w := ethwtest.NewTmpWallet()
+	accs := []wallet.Account{w.NewRandomAccount(rng), w.NewRandomAccount(rng)}
+	signed := testChannelState(t, accs, 1)
+	for i, acc := range accs {
+		sig, err := channel.Sign(acc, signed.State)
+		if err != nil {
+			t.Fatalf("signing state: %v", err)
+		}
+		signed.Sigs[i] = sig
+	}
+	req := testWatchRequest(t, signed, 0, 1)
+
+	resp, err := client.Watch(ctx, req)
+	if err != nil {
+		t.Fatalf("watching: %v", err)
+	}
+	if resp.Version != signed.State.Version {
+		t.Errorf("got response for version %d, want %d", resp.Version, signed.State.Version)
+	}
+
+	_, err = client.Fund(ctx, &proto.FundingRequestMsg{
+		Participant:      0,
+		Params:           req.State.Params,
+		InitialState:     req.State.State,
+		FundingAgreement: req.State.State.Allocation.Balances,
+	}, nil)
+	if err != nil {
+		t.Fatalf("funding: %v", err)
+	}
+	if len(funded) != 1 || funded[0].State.ID != signed.State.ID {
+		t.Errorf("got %d funding requests, want one for channel 0x%x", len(funded), signed.State.ID)
+	}
+}
+
+func TestClientResponseError(t *testing.T) {
+	client := testClient(t, funderFunc(blockingFund))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.ForceClose(ctx, &proto.ForceCloseRequestMsg{ChannelId: make([]byte, len(channel.ID{}))})
+	if !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("got error %v, want %v", err, ErrUnknownChannel)
+	}
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.Code != proto.ErrorCode_UNKNOWN_CHANNEL {
+		t.Errorf("got error %v, want response error with code %v", err, proto.ErrorCode_UNKNOWN_CHANNEL)
+	}
+}
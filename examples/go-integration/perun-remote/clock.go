@@ -0,0 +1,84 @@
+package remote
+
+import (
+	"context"
+	"time"
+
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	"perun.network/go-perun/channel"
+)
+
+// Clock is the time source the WatcherService and the DelayedAdjudicator wait
+// with. It can be replaced to test the timing of disputes without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock is the default Clock, using the system time.
+var RealClock Clock = realClock{}
+
+// WithClock makes the service wait with the clock instead of RealClock.
+func WithClock(clock Clock) WatcherOption {
+	return func(service *WatcherService) {
+		service.clock = clock
+	}
+}
+
+// timeoutTime returns the time at which the timeout elapses, for the timeouts
+// given by a point in time: channel.TimeTimeout and the eth backend's
+// BlockTimeout, which elapses with the first block at or after its timestamp.
+func timeoutTime(timeout channel.Timeout) (time.Time, bool) {
+	switch t := timeout.(type) {
+	case *channel.TimeTimeout:
+		return t.Time, true
+	case *ethchannel.BlockTimeout:
+		return time.Unix(int64(t.Time), 0), true
+	}
+	return time.Time{}, false
+}
+
+// clockedTimeout returns the time at which the timeout elapses if the clock
+// decides about it. The RealClock leaves timeouts to their own source, as
+// block timeouts only elapse with a block at or after their timestamp.
+func clockedTimeout(clock Clock, timeout channel.Timeout) (time.Time, bool) {
+	if _, ok := clock.(realClock); ok {
+		return time.Time{}, false
+	}
+	return timeoutTime(timeout)
+}
+
+// waitTimeout waits for the timeout in the background. The returned channel
+// receives the result of the wait. Timeouts are waited for with the service's
+// clock, unless it is the RealClock, see clockedTimeout.
+func (service *WatcherService) waitTimeout(ctx context.Context, timeout channel.Timeout) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		at, ok := clockedTimeout(service.clock, timeout)
+		if !ok {
+			done <- timeout.Wait(ctx)
+			return
+		}
+		select {
+		case <-service.clock.After(at.Sub(service.clock.Now())):
+			done <- nil
+		case <-ctx.Done():
+			done <- ctx.Err()
+		}
+	}()
+	return done
+}
+
+// timeoutElapsed returns whether the timeout has elapsed, checking it against
+// the service's clock like waitTimeout.
+func (service *WatcherService) timeoutElapsed(ctx context.Context, timeout channel.Timeout) bool {
+	if at, ok := clockedTimeout(service.clock, timeout); ok {
+		return !service.clock.Now().Before(at)
+	}
+	return timeout.IsElapsed(ctx)
+}
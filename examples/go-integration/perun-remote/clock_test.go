@@ -0,0 +1,146 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	"perun.network/go-perun/channel"
+)
+
+// fakeClock is a Clock which only advances when told to.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward, firing the waiters which are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = waiting
+}
+
+// numWaiters returns the number of pending After calls.
+func (c *fakeClock) numWaiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// awaitWaiters waits until n After calls are pending, so advancing the clock
+// fires them.
+func (c *fakeClock) awaitWaiters(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for c.numWaiters() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters on the clock, got %d", n, c.numWaiters())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	tests := []struct {
+		name    string
+		timeout channel.Timeout
+		elapsed bool // Whether the timeout is elapsed at start.
+	}{
+		{"time timeout", &channel.TimeTimeout{Time: start.Add(time.Minute)}, false},
+		{"block timeout", ethchannel.NewBlockTimeout(nil, uint64(start.Add(time.Minute).Unix())), false},
+		{"elapsed time timeout", &channel.TimeTimeout{Time: start.Add(-time.Minute)}, true},
+		{"elapsed block timeout", ethchannel.NewBlockTimeout(nil, uint64(start.Unix())), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock(start)
+			service := &WatcherService{clock: clock}
+
+			if got := service.timeoutElapsed(context.Background(), tt.timeout); got != tt.elapsed {
+				t.Fatalf("timeoutElapsed at start = %v, want %v", got, tt.elapsed)
+			}
+			done := service.waitTimeout(context.Background(), tt.timeout)
+			if !tt.elapsed {
+				clock.awaitWaiters(t, 1)
+				clock.Advance(time.Minute - time.Second)
+				select {
+				case <-done:
+					t.Fatal("timeout elapsed early")
+				case <-time.After(10 * time.Millisecond):
+				}
+				clock.Advance(time.Second)
+			}
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("waiting for timeout: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("timeout did not elapse")
+			}
+			if !service.timeoutElapsed(context.Background(), tt.timeout) {
+				t.Fatal("timeoutElapsed = false after the timeout")
+			}
+		})
+	}
+}
+
+func TestWaitTimeoutCanceled(t *testing.T) {
+	clock := newFakeClock(time.Unix(1_000_000, 0))
+	service := &WatcherService{clock: clock}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := service.waitTimeout(ctx, &channel.TimeTimeout{Time: clock.Now().Add(time.Minute)})
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait was not canceled")
+	}
+}
@@ -0,0 +1,74 @@
+// Package codec frames the messages of the remote protocol. It is shared by
+// the server and clients, so that both sides agree on the framing.
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	"go-integration/perun-remote/proto"
+)
+
+// DefaultMaxFrameSize is the default limit for the size of received messages.
+const DefaultMaxFrameSize = 1 << 20
+
+// Codec reads and writes messages framed as their size (big-endian uint32),
+// followed by the protobuf encoded message. The zero value uses
+// DefaultMaxFrameSize.
+type Codec struct {
+	// MaxFrameSize limits the size of read messages, zero for
+	// DefaultMaxFrameSize. Written messages are not limited.
+	MaxFrameSize uint32
+}
+
+// ReadMessage reads the next message from r.
+func (c Codec) ReadMessage(r io.Reader) (*proto.Message, error) {
+	maxSize := c.MaxFrameSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxFrameSize
+	}
+
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("reading size of data from wire: %w", err)
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("message size %d exceeds limit of %d", size, maxSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("reading data from wire: %w", err)
+	}
+	var msg proto.Message
+	if err := protobuf.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("unmarshalling message: %w", err)
+	}
+	return &msg, nil
+}
+
+// WriteMessage writes the message to w. The frame is written with a single
+// call, so concurrent writers only have to serialize the calls. A short write
+// is an error, as the rest of the stream would be misframed.
+func (c Codec) WriteMessage(w io.Writer, msg *proto.Message) error {
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshalling message: %w", err)
+	}
+	if uint64(len(data)) > math.MaxUint32 {
+		return fmt.Errorf("message too large: %d bytes", len(data))
+	}
+	frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+	frame = append(frame, data...)
+	n, err := w.Write(frame)
+	if err == nil && n < len(frame) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		return fmt.Errorf("writing data to wire (%d of %d bytes written): %w", n, len(frame), err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go-integration/perun-remote/proto"
+)
+
+// shortWriter accepts at most limit bytes per write without an error.
+type shortWriter struct {
+	limit int
+	buf   bytes.Buffer
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		p = p[:w.limit]
+	}
+	return w.buf.Write(p)
+}
+
+func pingMsg() *proto.Message {
+	return &proto.Message{Msg: &proto.Message_Ping{Ping: &proto.PingMsg{}}}
+}
+
+func TestWriteMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int
+		wantErr error
+	}{
+		{"full write", 1 << 10, nil},
+		{"short write", 3, io.ErrShortWrite},
+		{"nothing written", 0, io.ErrShortWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &shortWriter{limit: tt.limit}
+			err := Codec{}.WriteMessage(w, pingMsg())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := (Codec{}).ReadMessage(&w.buf); err != nil {
+				t.Fatalf("reading written message: %v", err)
+			}
+		})
+	}
+}
+
+// padding returns an encoded message of the given size, consisting of a
+// single unknown field.
+func padding(size int) []byte {
+	for n := size; n >= 0; n-- {
+		data := protowire.AppendTag(nil, 1000, protowire.BytesType)
+		data = protowire.AppendBytes(data, make([]byte, n))
+		if len(data) == size {
+			return data
+		}
+	}
+	panic("no padding of the size")
+}
+
+func TestReadMessageMaxFrameSize(t *testing.T) {
+	frame := func(size uint32) []byte {
+		return append(binary.BigEndian.AppendUint32(nil, size), padding(int(size))...)
+	}
+	tests := []struct {
+		name    string
+		max     uint32
+		data    []byte
+		wantErr bool
+	}{
+		{"below limit", 8, frame(4), false},
+		{"at limit", 8, frame(8), false},
+		{"above limit", 8, frame(9), true},
+		{"default limit", 0, frame(DefaultMaxFrameSize), false},
+		{"above default limit", 0, binary.BigEndian.AppendUint32(nil, DefaultMaxFrameSize+1), true},
+		{"truncated frame", 8, frame(8)[:6], true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Codec{MaxFrameSize: tt.max}.ReadMessage(bytes.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package remote
+
+import (
+	"context"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// DelayedAdjudicator delays the registration of states by a fixed reaction
+// delay. Passed to the watcher of a WatcherService, it simulates a slow
+// watcher which refutes disputes late, e.g. to test behavior near the end of
+// a refutation window. All other calls are passed through.
+type DelayedAdjudicator struct {
+	channel.Adjudicator
+	Delay time.Duration
+	// Clock is used to wait for the delay, RealClock if nil. It can be
+	// replaced to control the delay in tests.
+	Clock Clock
+}
+
+func NewDelayedAdjudicator(adj channel.Adjudicator, delay time.Duration) *DelayedAdjudicator {
+	return &DelayedAdjudicator{Adjudicator: adj, Delay: delay, Clock: RealClock}
+}
+
+// Register waits for the reaction delay and then registers the state, unless
+// ctx is done before.
+func (a *DelayedAdjudicator) Register(ctx context.Context, req channel.AdjudicatorReq, subChannels []channel.SignedState) error {
+	if a.Delay > 0 {
+		clock := a.Clock
+		if clock == nil {
+			clock = RealClock
+		}
+		channelLogger(req.Params.ID()).Warnf("Delaying registration of version %d by %v", req.Tx.Version, a.Delay)
+		select {
+		case <-clock.After(a.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return a.Adjudicator.Register(ctx, req, subChannels)
+}
@@ -0,0 +1,83 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// notifyingAdjudicator signals each registration. Other adjudicator methods are
+// not implemented.
+type notifyingAdjudicator struct {
+	channel.Adjudicator
+	registered chan struct{}
+}
+
+func (a notifyingAdjudicator) Register(context.Context, channel.AdjudicatorReq, []channel.SignedState) error {
+	a.registered <- struct{}{}
+	return nil
+}
+
+func TestDelayedAdjudicatorRegister(t *testing.T) {
+	signed := testSignedState(t, 1)
+	req := channel.AdjudicatorReq{Params: signed.Params, Tx: channel.Transaction{State: signed.State}}
+	adj := notifyingAdjudicator{registered: make(chan struct{}, 1)}
+	clock := newFakeClock(time.Unix(1_000_000, 0))
+	delayed := NewDelayedAdjudicator(adj, time.Hour)
+	delayed.Clock = clock
+
+	errs := make(chan error, 1)
+	go func() { errs <- delayed.Register(context.Background(), req, nil) }()
+	clock.awaitWaiters(t, 1)
+	clock.Advance(time.Hour - time.Second)
+	select {
+	case <-adj.registered:
+		t.Fatal("registered before the delay passed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	if err := <-errs; err != nil {
+		t.Fatalf("registering: %v", err)
+	}
+	select {
+	case <-adj.registered:
+	default:
+		t.Fatal("not registered after the delay passed")
+	}
+
+	// Registering is aborted if the context is done during the delay.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := delayed.Register(ctx, req, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-adj.registered:
+		t.Fatal("registered after the context was canceled")
+	default:
+	}
+}
+
+func TestDelayedAdjudicatorNoDelay(t *testing.T) {
+	signed := testSignedState(t, 1)
+	req := channel.AdjudicatorReq{Params: signed.Params, Tx: channel.Transaction{State: signed.State}}
+	adj := notifyingAdjudicator{registered: make(chan struct{}, 1)}
+	clock := newFakeClock(time.Unix(1_000_000, 0))
+	delayed := NewDelayedAdjudicator(adj, 0)
+	delayed.Clock = clock
+
+	if err := delayed.Register(context.Background(), req, nil); err != nil {
+		t.Fatalf("registering: %v", err)
+	}
+	select {
+	case <-adj.registered:
+	default:
+		t.Fatal("not registered")
+	}
+	if n := clock.numWaiters(); n != 0 {
+		t.Errorf("got %d waiters on the clock, want none", n)
+	}
+}
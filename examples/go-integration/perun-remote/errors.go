@@ -0,0 +1,33 @@
+package remote
+
+import (
+	"errors"
+
+	"go-integration/perun-remote/proto"
+)
+
+var (
+	ErrInvalidRequest  = errors.New("invalid request")
+	ErrUnknownChannel  = errors.New("unknown channel")
+	ErrOutdatedVersion = errors.New("outdated version")
+)
+
+// errorResponse returns the error string and code sent to the client for the
+// error returned by a handler. Errors not matching a known cause are reported
+// with the given fallback code.
+func errorResponse(err error, fallback proto.ErrorCode) (string, proto.ErrorCode) {
+	switch {
+	case err == nil:
+		return "", proto.ErrorCode_OK
+	case errors.Is(err, ErrInvalidRequest):
+		return err.Error(), proto.ErrorCode_INVALID_REQUEST
+	case errors.Is(err, ErrUnknownChannel):
+		return err.Error(), proto.ErrorCode_UNKNOWN_CHANNEL
+	case errors.Is(err, ErrOutdatedVersion):
+		return err.Error(), proto.ErrorCode_OUTDATED_VERSION
+	case errors.Is(err, ErrFundingTimeout):
+		return err.Error(), proto.ErrorCode_FUNDING_TIMEOUT
+	default:
+		return err.Error(), fallback
+	}
+}
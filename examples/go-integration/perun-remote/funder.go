@@ -2,18 +2,215 @@ package remote
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"perun.network/go-perun/channel"
 )
 
+// DefaultFundingTimeout is the default time a funding request may take.
+const DefaultFundingTimeout = 5 * time.Minute
+
+// ErrFundingTimeout is returned if funding did not complete within the
+// funding timeout.
+var ErrFundingTimeout = errors.New("funding timed out")
+
 type FunderService struct {
-	funder channel.Funder
+	funder   channel.Funder
+	timeout  time.Duration
+	retry    RetryPolicy
+	observer FundingObserver // nil if funding is not observed
+	metrics  FunderMetrics
+}
+
+// RetryPolicy controls retrying funding after transient errors.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of funding attempts, values below 2
+	// disable retrying.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, which doubles with
+	// every further retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Transient lists substrings of error messages which are retried.
+	Transient []string
+}
+
+// DefaultRetryPolicy retries the errors ganache and geth return for
+// transactions racing for the same nonce.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Transient: []string{
+			"nonce too low",
+			"replacement transaction underpriced",
+			"already known",
+		},
+	}
+}
+
+func (p RetryPolicy) isTransient(err error) bool {
+	for _, s := range p.Transient {
+		if strings.Contains(err.Error(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// FunderOption configures optional behavior of a FunderService.
+type FunderOption func(*FunderService)
+
+// WithFundingTimeout sets the time a funding request may take. A zero timeout
+// disables the deadline.
+func WithFundingTimeout(timeout time.Duration) FunderOption {
+	return func(f *FunderService) {
+		f.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the policy for retrying funding after transient errors.
+// Retries are bounded by the funding timeout.
+func WithRetryPolicy(policy RetryPolicy) FunderOption {
+	return func(f *FunderService) {
+		f.retry = policy
+	}
 }
 
-func NewFunderService(funder channel.Funder) *FunderService {
-	return &FunderService{funder: funder}
+// WithFundingObserver reports the progress of funding requests per asset as
+// observed by o. Without an observer, all assets are reported once funding
+// completed.
+func WithFundingObserver(o FundingObserver) FunderOption {
+	return func(f *FunderService) {
+		f.observer = o
+	}
 }
 
-func (f *FunderService) Fund(ctx context.Context, req channel.FundingReq) error {
-	return f.funder.Fund(ctx, req)
+// WithFunderMetrics reports the handled funding requests to m.
+func WithFunderMetrics(m FunderMetrics) FunderOption {
+	return func(f *FunderService) {
+		f.metrics = m
+	}
+}
+
+func NewFunderService(funder channel.Funder, opts ...FunderOption) *FunderService {
+	f := &FunderService{
+		funder:  funder,
+		timeout: DefaultFundingTimeout,
+		retry:   DefaultRetryPolicy(),
+		metrics: noMetrics{},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// FundingProgress reports that the funding of an asset completed.
+type FundingProgress struct {
+	Asset     int // Index of the funded asset.
+	Confirmed int // Number of funded assets, including this one.
+	Total     int
+}
+
+// Fund funds the channel. If onProgress is not nil, it is called each time the
+// funding of an asset completed, see WithFundingObserver. Funding is abandoned
+// with ErrFundingTimeout when the deadline of ctx or the funding timeout
+// passes, whichever comes first.
+func (f *FunderService) Fund(ctx context.Context, req channel.FundingReq, onProgress func(FundingProgress)) error {
+	start := time.Now()
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	var err error
+	if onProgress == nil {
+		err = f.fundWithRetry(ctx, req)
+	} else {
+		err = f.fundReporting(ctx, req, onProgress)
+	}
+	if err != nil {
+		f.metrics.FundingFailed()
+	} else {
+		f.metrics.FundingSucceeded()
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %v: %v", ErrFundingTimeout, time.Since(start).Round(time.Millisecond), err)
+	}
+	return err
+}
+
+// fundWithRetry funds, retrying transient errors according to the retry
+// policy until ctx is done.
+func (f *FunderService) fundWithRetry(ctx context.Context, req channel.FundingReq) error {
+	backoff := f.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := f.funder.Fund(ctx, req)
+		if err == nil || attempt >= f.retry.MaxAttempts || !f.retry.isTransient(err) {
+			return err
+		}
+		log.Warnf("Funder: attempt %d failed with transient error, retrying in %v: %v", attempt, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > f.retry.MaxBackoff {
+			backoff = f.retry.MaxBackoff
+		}
+	}
+}
+
+// fundReporting funds like fundWithRetry and reports the progress of the
+// assets the observer sees funded meanwhile. The remaining assets are reported
+// once funding succeeded. onProgress is not called after fundReporting
+// returned.
+func (f *FunderService) fundReporting(ctx context.Context, req channel.FundingReq, onProgress func(FundingProgress)) error {
+	total := len(req.State.Assets)
+	var (
+		mu        sync.Mutex
+		reported  = make([]bool, total)
+		confirmed int
+	)
+	report := func(asset int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if reported[asset] {
+			return
+		}
+		reported[asset] = true
+		confirmed++
+		onProgress(FundingProgress{Asset: asset, Confirmed: confirmed, Total: total})
+	}
+
+	if f.observer != nil {
+		observeCtx, cancel := context.WithCancel(ctx)
+		observed := make(chan struct{})
+		go func() {
+			defer close(observed)
+			f.observer.ObserveFunding(observeCtx, req, report)
+		}()
+		defer func() {
+			cancel()
+			<-observed
+		}()
+	}
+
+	if err := f.fundWithRetry(ctx, req); err != nil {
+		return err
+	}
+	for asset := 0; asset < total; asset++ {
+		report(asset)
+	}
+	return nil
 }
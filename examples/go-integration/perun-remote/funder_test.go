@@ -0,0 +1,115 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"perun.network/go-perun/channel"
+)
+
+// funderFunc is a channel.Funder calling the function.
+type funderFunc func(context.Context, channel.FundingReq) error
+
+func (f funderFunc) Fund(ctx context.Context, req channel.FundingReq) error {
+	return f(ctx, req)
+}
+
+// blockingFund blocks until ctx is done.
+func blockingFund(ctx context.Context, _ channel.FundingReq) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+var errFundingFailed = errors.New("funding failed")
+
+func TestFundTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		fund    funderFunc
+		timeout time.Duration
+		// Deadline of the caller's context, zero for none.
+		ctxTimeout time.Duration
+		report     bool // Whether progress is reported.
+		wantErr    error
+		noTimeout  bool // Whether the error must not be ErrFundingTimeout.
+	}{
+		{
+			name:    "funded",
+			fund:    func(context.Context, channel.FundingReq) error { return nil },
+			timeout: time.Minute,
+		},
+		{
+			name:    "funding timeout",
+			fund:    blockingFund,
+			timeout: 10 * time.Millisecond,
+			wantErr: ErrFundingTimeout,
+		},
+		{
+			name:    "funding timeout while reporting",
+			fund:    blockingFund,
+			timeout: 10 * time.Millisecond,
+			report:  true,
+			wantErr: ErrFundingTimeout,
+		},
+		{
+			name:       "caller deadline before funding timeout",
+			fund:       blockingFund,
+			timeout:    time.Minute,
+			ctxTimeout: 10 * time.Millisecond,
+			wantErr:    ErrFundingTimeout,
+		},
+		{
+			name:      "failure before timeout",
+			fund:      func(context.Context, channel.FundingReq) error { return errFundingFailed },
+			timeout:   time.Minute,
+			wantErr:   errFundingFailed,
+			noTimeout: true,
+		},
+		{
+			name: "zero timeout disables the deadline",
+			fund: func(ctx context.Context, _ channel.FundingReq) error {
+				if _, ok := ctx.Deadline(); ok {
+					return errFundingFailed
+				}
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFunderService(tt.fund, WithFundingTimeout(tt.timeout))
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			var onProgress func(FundingProgress)
+			if tt.report {
+				onProgress = func(FundingProgress) {}
+			}
+			req := channel.FundingReq{State: &channel.State{}}
+
+			err := f.Fund(ctx, req, onProgress)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.noTimeout && errors.Is(err, ErrFundingTimeout) {
+				t.Fatalf("got %v for a failure before the timeout", err)
+			}
+		})
+	}
+}
+
+func TestFundCanceled(t *testing.T) {
+	f := NewFunderService(funderFunc(blockingFund), WithFundingTimeout(time.Minute))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := f.Fund(ctx, channel.FundingReq{}, nil)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrFundingTimeout) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,101 @@
+package remote
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/perun-network/perun-eth-backend/bindings/assetholder"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	"perun.network/go-perun/channel"
+)
+
+// FundingObserver observes the on-chain funding of a channel while the
+// FunderService funds it, so progress can be reported per asset.
+type FundingObserver interface {
+	// ObserveFunding calls onFunded with the index of each asset once the
+	// funding agreement of the asset is deposited, until all observed assets
+	// are funded or ctx is done.
+	ObserveFunding(ctx context.Context, req channel.FundingReq, onFunded func(asset int))
+}
+
+// HoldingsObserver is a FundingObserver polling the holdings of the
+// participants in the asset holders of the channel's assets.
+type HoldingsObserver struct {
+	backend  bind.ContractCaller
+	interval time.Duration
+}
+
+// NewHoldingsObserver returns an observer reading the holdings from backend
+// every interval.
+func NewHoldingsObserver(backend bind.ContractCaller, interval time.Duration) *HoldingsObserver {
+	return &HoldingsObserver{backend: backend, interval: interval}
+}
+
+// ObserveFunding implements FundingObserver. Assets which are not Ethereum
+// assets are not observed.
+func (o *HoldingsObserver) ObserveFunding(ctx context.Context, req channel.FundingReq, onFunded func(asset int)) {
+	fundingIDs := ethchannel.FundingIDs(req.Params.ID(), req.Params.Parts...)
+	holders := make(map[int]*assetholder.AssetholderCaller)
+	for i, asset := range req.State.Assets {
+		ethAsset, ok := asset.(*ethchannel.Asset)
+		if !ok {
+			continue
+		}
+		holder, err := assetholder.NewAssetholderCaller(common.Address(ethAsset.AssetHolder), o.backend)
+		if err != nil {
+			log.Warnf("Funder: not observing the funding of asset %d: %v", i, err)
+			continue
+		}
+		holders[i] = holder
+	}
+
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	for len(holders) > 0 {
+		for _, i := range sortedKeys(holders) {
+			funded, err := o.funded(ctx, holders[i], req.Agreement[i], fundingIDs)
+			if err != nil {
+				log.Debugf("Funder: reading holdings of asset %d: %v", i, err)
+				continue
+			}
+			if funded {
+				delete(holders, i)
+				onFunded(i)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// funded returns whether every participant holds at least its agreed share in
+// the asset holder.
+func (o *HoldingsObserver) funded(ctx context.Context, holder *assetholder.AssetholderCaller, agreement []channel.Bal, fundingIDs [][32]byte) (bool, error) {
+	for j, agreed := range agreement {
+		held, err := holder.Holdings(&bind.CallOpts{Context: ctx}, fundingIDs[j])
+		if err != nil {
+			return false, err
+		}
+		if held.Cmp(agreed) < 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func sortedKeys(m map[int]*assetholder.AssetholderCaller) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,111 @@
+package remote
+
+import (
+	"fmt"
+
+	"go-integration/perun-remote/proto"
+
+	perunProto "perun.network/go-perun/wire/protobuf"
+)
+
+// Limits bounds the size of the channels in requests, which are checked
+// before any request is decoded. Together with the frame size limit of the
+// server, this keeps clients from exhausting the server with huge requests,
+// e.g. with a withdrawal auth for millions of assets.
+type Limits struct {
+	MaxParticipants int
+	MaxAssets       int
+}
+
+// DefaultLimits are the limits used by a server unless configured otherwise.
+var DefaultLimits = Limits{
+	MaxParticipants: 16,
+	MaxAssets:       64,
+}
+
+// ParseWatchRequestMsg parses the request if it is within the limits.
+func (l Limits) ParseWatchRequestMsg(p *proto.WatchRequestMsg) (*WatchRequestMsg, error) {
+	if err := l.checkWatchRequest(p); err != nil {
+		return nil, err
+	}
+	return ParseWatchRequestMsg(p)
+}
+
+// ParseWatchUpdateMsg parses the update if it is within the limits.
+func (l Limits) ParseWatchUpdateMsg(p *proto.WatchUpdateMsg) (*WatchUpdateMsg, error) {
+	if err := l.checkWatchRequest(p.GetRequest()); err != nil {
+		return nil, err
+	}
+	return ParseWatchUpdateMsg(p)
+}
+
+// ParseForceCloseRequestMsg parses the request if it is within the limits.
+func (l Limits) ParseForceCloseRequestMsg(p *proto.ForceCloseRequestMsg) (*ForceCloseRequestMsg, error) {
+	if p.Latest != nil {
+		if err := l.checkWatchRequest(p.Latest); err != nil {
+			return nil, err
+		}
+	}
+	return ParseForceCloseRequestMsg(p)
+}
+
+// ParseFundingRequestMsg parses the request if it is within the limits.
+func (l Limits) ParseFundingRequestMsg(p *proto.FundingRequestMsg) (*FundingRequestMsg, error) {
+	if err := l.checkParticipants(len(p.GetParams().GetParts())); err != nil {
+		return nil, err
+	}
+	if err := l.checkAllocation(p.GetInitialState().GetAllocation()); err != nil {
+		return nil, err
+	}
+	if err := l.checkBalances(p.GetFundingAgreement()); err != nil {
+		return nil, fmt.Errorf("funding agreement: %w", err)
+	}
+	return ParseFundingRequestMsg(p)
+}
+
+func (l Limits) checkWatchRequest(p *proto.WatchRequestMsg) error {
+	signed := p.GetState()
+	if err := l.checkParticipants(len(signed.GetParams().GetParts())); err != nil {
+		return err
+	}
+	if err := l.checkParticipants(len(signed.GetSigs())); err != nil {
+		return fmt.Errorf("signatures: %w", err)
+	}
+	if err := l.checkAssets(len(p.GetWithdrawalAuths())); err != nil {
+		return fmt.Errorf("withdrawal auths: %w", err)
+	}
+	return l.checkAllocation(signed.GetState().GetAllocation())
+}
+
+func (l Limits) checkAllocation(alloc *perunProto.Allocation) error {
+	if err := l.checkAssets(len(alloc.GetAssets())); err != nil {
+		return err
+	}
+	return l.checkBalances(alloc.GetBalances())
+}
+
+func (l Limits) checkBalances(bals *perunProto.Balances) error {
+	if err := l.checkAssets(len(bals.GetBalances())); err != nil {
+		return err
+	}
+	for _, bal := range bals.GetBalances() {
+		if err := l.checkParticipants(len(bal.GetBalance())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l Limits) checkParticipants(n int) error {
+	if n > l.MaxParticipants {
+		return fmt.Errorf("%d participants exceed the limit of %d", n, l.MaxParticipants)
+	}
+	return nil
+}
+
+func (l Limits) checkAssets(n int) error {
+	if n > l.MaxAssets {
+		return fmt.Errorf("%d assets exceed the limit of %d", n, l.MaxAssets)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package remote
+
+import (
+	"sync/atomic"
+)
+
+// Metrics receives the events of a WatcherService for monitoring, e.g. to be
+// exported as Prometheus counters and gauges. Implementations have to be safe
+// for concurrent use.
+type Metrics interface {
+	// WatchStarted and WatchEnded are called when the service starts and
+	// stops watching a channel, their difference is the number of watched
+	// channels.
+	WatchStarted()
+	WatchEnded()
+	// DisputeStarted is called when a state was registered on request of the
+	// client.
+	DisputeStarted()
+	WithdrawalSucceeded()
+	WithdrawalFailed()
+}
+
+// FunderMetrics receives the events of a FunderService for monitoring.
+// Implementations have to be safe for concurrent use.
+type FunderMetrics interface {
+	// FundingSucceeded and FundingFailed are called when a funding request
+	// was handled.
+	FundingSucceeded()
+	FundingFailed()
+}
+
+type noMetrics struct{}
+
+func (noMetrics) WatchStarted()        {}
+func (noMetrics) WatchEnded()          {}
+func (noMetrics) DisputeStarted()      {}
+func (noMetrics) WithdrawalSucceeded() {}
+func (noMetrics) WithdrawalFailed()    {}
+func (noMetrics) FundingSucceeded()    {}
+func (noMetrics) FundingFailed()       {}
+
+// Counters is a Metrics and FunderMetrics implementation keeping the values
+// in memory.
+type Counters struct {
+	watched            atomic.Int64
+	disputes           atomic.Uint64
+	withdrawals        atomic.Uint64
+	withdrawalFailures atomic.Uint64
+	fundings           atomic.Uint64
+	fundingFailures    atomic.Uint64
+}
+
+var (
+	_ Metrics       = (*Counters)(nil)
+	_ FunderMetrics = (*Counters)(nil)
+)
+
+func (c *Counters) WatchStarted()        { c.watched.Add(1) }
+func (c *Counters) WatchEnded()          { c.watched.Add(-1) }
+func (c *Counters) DisputeStarted()      { c.disputes.Add(1) }
+func (c *Counters) WithdrawalSucceeded() { c.withdrawals.Add(1) }
+func (c *Counters) WithdrawalFailed()    { c.withdrawalFailures.Add(1) }
+func (c *Counters) FundingSucceeded()    { c.fundings.Add(1) }
+func (c *Counters) FundingFailed()       { c.fundingFailures.Add(1) }
+
+// CountersSnapshot holds the values of Counters at a point in time.
+type CountersSnapshot struct {
+	Watched            int64
+	Disputes           uint64
+	Withdrawals        uint64
+	WithdrawalFailures uint64
+	Fundings           uint64
+	FundingFailures    uint64
+}
+
+// Snapshot returns the current values of the counters.
+func (c *Counters) Snapshot() CountersSnapshot {
+	return CountersSnapshot{
+		Watched:            c.watched.Load(),
+		Disputes:           c.disputes.Load(),
+		Withdrawals:        c.withdrawals.Load(),
+		WithdrawalFailures: c.withdrawalFailures.Load(),
+		Fundings:           c.fundings.Load(),
+		FundingFailures:    c.fundingFailures.Load(),
+	}
+}
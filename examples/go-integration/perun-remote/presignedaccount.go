@@ -22,14 +22,23 @@ func NewPreSignedAccount(addr wallet.Address) *PreSignedAccount {
 
 func (p PreSignedAccount) Address() wallet.Address { return p.address }
 
+// AddSig stores the signature of the message, which is returned by SignData
+// when asked to sign exactly the same bytes, e.g. an encoded WithdrawalAuth
+// (see EncodeWithdrawalAuth).
 func (p *PreSignedAccount) AddSig(message []byte, sig wallet.Sig) {
-	p.signatures[string(message)] = sig
+	p.signatures[sigKey(message)] = sig
 }
 
 func (p *PreSignedAccount) SignData(message []byte) ([]byte, error) {
-	if sig, ok := p.signatures[string(message)]; ok {
+	if sig, ok := p.signatures[sigKey(message)]; ok {
 		return sig, nil
 	}
 
 	return nil, errors.New("PreSignedAccount: unanticipated request.")
 }
+
+// sigKey returns the map key of a message. The conversion copies the bytes,
+// so later modifications of the message do not affect the stored signatures.
+func sigKey(message []byte) string {
+	return string(message)
+}
@@ -0,0 +1,37 @@
+package remote
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	"perun.network/go-perun/wallet"
+)
+
+func TestPreSignedAccountSignData(t *testing.T) {
+	acc := NewPreSignedAccount(ethwallet.AsWalletAddr(common.Address{1}))
+	message := []byte("withdrawal auth")
+	sig := wallet.Sig{1, 2, 3}
+	acc.AddSig(message, sig)
+
+	// The signature is found by the content of the message, not the slice.
+	got, err := acc.SignData(append([]byte(nil), message...))
+	if err != nil || !bytes.Equal(got, sig) {
+		t.Fatalf("signing the same bytes: got %x, %v, want %x", got, err, sig)
+	}
+
+	// Modifying the message after adding its signature does not affect the
+	// stored signature.
+	message[0] = 'W'
+	if _, err := acc.SignData(message); err == nil {
+		t.Errorf("signed the modified message")
+	}
+	if got, err := acc.SignData([]byte("withdrawal auth")); err != nil || !bytes.Equal(got, sig) {
+		t.Errorf("signing the original bytes after modifying the message: got %x, %v", got, err)
+	}
+
+	if _, err := acc.SignData([]byte("other message")); err == nil {
+		t.Errorf("signed an unanticipated message")
+	}
+}
@@ -21,6 +21,221 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ChannelType int32
+
+const (
+	ChannelType_LEDGER ChannelType = 0
+	ChannelType_SUB    ChannelType = 1
+	// Not supported by the watcher yet.
+	ChannelType_VIRTUAL ChannelType = 2
+)
+
+// Enum value maps for ChannelType.
+var (
+	ChannelType_name = map[int32]string{
+		0: "LEDGER",
+		1: "SUB",
+		2: "VIRTUAL",
+	}
+	ChannelType_value = map[string]int32{
+		"LEDGER":  0,
+		"SUB":     1,
+		"VIRTUAL": 2,
+	}
+)
+
+func (x ChannelType) Enum() *ChannelType {
+	p := new(ChannelType)
+	*p = x
+	return p
+}
+
+func (x ChannelType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelType) Descriptor() protoreflect.EnumDescriptor {
+	return file_perun_remote_proto_enumTypes[0].Descriptor()
+}
+
+func (ChannelType) Type() protoreflect.EnumType {
+	return &file_perun_remote_proto_enumTypes[0]
+}
+
+func (x ChannelType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelType.Descriptor instead.
+func (ChannelType) EnumDescriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{0}
+}
+
+// Classifies the failure of a request, so clients can react without parsing
+// the error string.
+type ErrorCode int32
+
+const (
+	ErrorCode_OK               ErrorCode = 0
+	ErrorCode_INVALID_REQUEST  ErrorCode = 1
+	ErrorCode_UNKNOWN_CHANNEL  ErrorCode = 2
+	ErrorCode_OUTDATED_VERSION ErrorCode = 3
+	ErrorCode_FUNDING_FAILED   ErrorCode = 4
+	ErrorCode_DISPUTE_FAILED   ErrorCode = 5
+	ErrorCode_INTERNAL_ERROR   ErrorCode = 6
+	ErrorCode_FUNDING_TIMEOUT  ErrorCode = 7
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "OK",
+		1: "INVALID_REQUEST",
+		2: "UNKNOWN_CHANNEL",
+		3: "OUTDATED_VERSION",
+		4: "FUNDING_FAILED",
+		5: "DISPUTE_FAILED",
+		6: "INTERNAL_ERROR",
+		7: "FUNDING_TIMEOUT",
+	}
+	ErrorCode_value = map[string]int32{
+		"OK":               0,
+		"INVALID_REQUEST":  1,
+		"UNKNOWN_CHANNEL":  2,
+		"OUTDATED_VERSION": 3,
+		"FUNDING_FAILED":   4,
+		"DISPUTE_FAILED":   5,
+		"INTERNAL_ERROR":   6,
+		"FUNDING_TIMEOUT":  7,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_perun_remote_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_perun_remote_proto_enumTypes[1]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{1}
+}
+
+type DisputeEvent int32
+
+const (
+	DisputeEvent_REGISTERED DisputeEvent = 0
+	DisputeEvent_PROGRESSED DisputeEvent = 1
+)
+
+// Enum value maps for DisputeEvent.
+var (
+	DisputeEvent_name = map[int32]string{
+		0: "REGISTERED",
+		1: "PROGRESSED",
+	}
+	DisputeEvent_value = map[string]int32{
+		"REGISTERED": 0,
+		"PROGRESSED": 1,
+	}
+)
+
+func (x DisputeEvent) Enum() *DisputeEvent {
+	p := new(DisputeEvent)
+	*p = x
+	return p
+}
+
+func (x DisputeEvent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeEvent) Descriptor() protoreflect.EnumDescriptor {
+	return file_perun_remote_proto_enumTypes[2].Descriptor()
+}
+
+func (DisputeEvent) Type() protoreflect.EnumType {
+	return &file_perun_remote_proto_enumTypes[2]
+}
+
+func (x DisputeEvent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeEvent.Descriptor instead.
+func (DisputeEvent) EnumDescriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{2}
+}
+
+// Phase of a channel in the adjudicator.
+type DisputePhase int32
+
+const (
+	DisputePhase_NO_DISPUTE DisputePhase = 0
+	DisputePhase_DISPUTE    DisputePhase = 1
+	DisputePhase_FORCE_EXEC DisputePhase = 2
+	DisputePhase_CONCLUDED  DisputePhase = 3
+)
+
+// Enum value maps for DisputePhase.
+var (
+	DisputePhase_name = map[int32]string{
+		0: "NO_DISPUTE",
+		1: "DISPUTE",
+		2: "FORCE_EXEC",
+		3: "CONCLUDED",
+	}
+	DisputePhase_value = map[string]int32{
+		"NO_DISPUTE": 0,
+		"DISPUTE":    1,
+		"FORCE_EXEC": 2,
+		"CONCLUDED":  3,
+	}
+)
+
+func (x DisputePhase) Enum() *DisputePhase {
+	p := new(DisputePhase)
+	*p = x
+	return p
+}
+
+func (x DisputePhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputePhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_perun_remote_proto_enumTypes[3].Descriptor()
+}
+
+func (DisputePhase) Type() protoreflect.EnumType {
+	return &file_perun_remote_proto_enumTypes[3]
+}
+
+func (x DisputePhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputePhase.Descriptor instead.
+func (DisputePhase) EnumDescriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{3}
+}
+
 type Message struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -34,6 +249,20 @@ type Message struct {
 	//	*Message_ForceCloseRequest
 	//	*Message_ForceCloseResponse
 	//	*Message_DisputeNotification
+	//	*Message_HandshakeRequest
+	//	*Message_HandshakeResponse
+	//	*Message_StopWatchRequest
+	//	*Message_StopWatchResponse
+	//	*Message_FundingProgress
+	//	*Message_WatchUpdate
+	//	*Message_Ping
+	//	*Message_Pong
+	//	*Message_StatusRequest
+	//	*Message_StatusResponse
+	//	*Message_DisputeStatusRequest
+	//	*Message_DisputeStatusResponse
+	//	*Message_ListWatchedRequest
+	//	*Message_ListWatchedResponse
 	Msg isMessage_Msg `protobuf_oneof:"msg"`
 }
 
@@ -125,6 +354,104 @@ func (x *Message) GetDisputeNotification() *DisputeNotification {
 	return nil
 }
 
+func (x *Message) GetHandshakeRequest() *HandshakeRequestMsg {
+	if x, ok := x.GetMsg().(*Message_HandshakeRequest); ok {
+		return x.HandshakeRequest
+	}
+	return nil
+}
+
+func (x *Message) GetHandshakeResponse() *HandshakeResponseMsg {
+	if x, ok := x.GetMsg().(*Message_HandshakeResponse); ok {
+		return x.HandshakeResponse
+	}
+	return nil
+}
+
+func (x *Message) GetStopWatchRequest() *StopWatchRequestMsg {
+	if x, ok := x.GetMsg().(*Message_StopWatchRequest); ok {
+		return x.StopWatchRequest
+	}
+	return nil
+}
+
+func (x *Message) GetStopWatchResponse() *StopWatchResponseMsg {
+	if x, ok := x.GetMsg().(*Message_StopWatchResponse); ok {
+		return x.StopWatchResponse
+	}
+	return nil
+}
+
+func (x *Message) GetFundingProgress() *FundingProgressMsg {
+	if x, ok := x.GetMsg().(*Message_FundingProgress); ok {
+		return x.FundingProgress
+	}
+	return nil
+}
+
+func (x *Message) GetWatchUpdate() *WatchUpdateMsg {
+	if x, ok := x.GetMsg().(*Message_WatchUpdate); ok {
+		return x.WatchUpdate
+	}
+	return nil
+}
+
+func (x *Message) GetPing() *PingMsg {
+	if x, ok := x.GetMsg().(*Message_Ping); ok {
+		return x.Ping
+	}
+	return nil
+}
+
+func (x *Message) GetPong() *PongMsg {
+	if x, ok := x.GetMsg().(*Message_Pong); ok {
+		return x.Pong
+	}
+	return nil
+}
+
+func (x *Message) GetStatusRequest() *StatusRequestMsg {
+	if x, ok := x.GetMsg().(*Message_StatusRequest); ok {
+		return x.StatusRequest
+	}
+	return nil
+}
+
+func (x *Message) GetStatusResponse() *StatusResponseMsg {
+	if x, ok := x.GetMsg().(*Message_StatusResponse); ok {
+		return x.StatusResponse
+	}
+	return nil
+}
+
+func (x *Message) GetDisputeStatusRequest() *DisputeStatusRequestMsg {
+	if x, ok := x.GetMsg().(*Message_DisputeStatusRequest); ok {
+		return x.DisputeStatusRequest
+	}
+	return nil
+}
+
+func (x *Message) GetDisputeStatusResponse() *DisputeStatusResponseMsg {
+	if x, ok := x.GetMsg().(*Message_DisputeStatusResponse); ok {
+		return x.DisputeStatusResponse
+	}
+	return nil
+}
+
+func (x *Message) GetListWatchedRequest() *ListWatchedRequestMsg {
+	if x, ok := x.GetMsg().(*Message_ListWatchedRequest); ok {
+		return x.ListWatchedRequest
+	}
+	return nil
+}
+
+func (x *Message) GetListWatchedResponse() *ListWatchedResponseMsg {
+	if x, ok := x.GetMsg().(*Message_ListWatchedResponse); ok {
+		return x.ListWatchedResponse
+	}
+	return nil
+}
+
 type isMessage_Msg interface {
 	isMessage_Msg()
 }
@@ -157,6 +484,62 @@ type Message_DisputeNotification struct {
 	DisputeNotification *DisputeNotification `protobuf:"bytes,7,opt,name=dispute_notification,json=disputeNotification,proto3,oneof"`
 }
 
+type Message_HandshakeRequest struct {
+	HandshakeRequest *HandshakeRequestMsg `protobuf:"bytes,8,opt,name=handshake_request,json=handshakeRequest,proto3,oneof"`
+}
+
+type Message_HandshakeResponse struct {
+	HandshakeResponse *HandshakeResponseMsg `protobuf:"bytes,9,opt,name=handshake_response,json=handshakeResponse,proto3,oneof"`
+}
+
+type Message_StopWatchRequest struct {
+	StopWatchRequest *StopWatchRequestMsg `protobuf:"bytes,10,opt,name=stop_watch_request,json=stopWatchRequest,proto3,oneof"`
+}
+
+type Message_StopWatchResponse struct {
+	StopWatchResponse *StopWatchResponseMsg `protobuf:"bytes,11,opt,name=stop_watch_response,json=stopWatchResponse,proto3,oneof"`
+}
+
+type Message_FundingProgress struct {
+	FundingProgress *FundingProgressMsg `protobuf:"bytes,12,opt,name=funding_progress,json=fundingProgress,proto3,oneof"`
+}
+
+type Message_WatchUpdate struct {
+	WatchUpdate *WatchUpdateMsg `protobuf:"bytes,13,opt,name=watch_update,json=watchUpdate,proto3,oneof"`
+}
+
+type Message_Ping struct {
+	Ping *PingMsg `protobuf:"bytes,14,opt,name=ping,proto3,oneof"`
+}
+
+type Message_Pong struct {
+	Pong *PongMsg `protobuf:"bytes,15,opt,name=pong,proto3,oneof"`
+}
+
+type Message_StatusRequest struct {
+	StatusRequest *StatusRequestMsg `protobuf:"bytes,16,opt,name=status_request,json=statusRequest,proto3,oneof"`
+}
+
+type Message_StatusResponse struct {
+	StatusResponse *StatusResponseMsg `protobuf:"bytes,17,opt,name=status_response,json=statusResponse,proto3,oneof"`
+}
+
+type Message_DisputeStatusRequest struct {
+	DisputeStatusRequest *DisputeStatusRequestMsg `protobuf:"bytes,18,opt,name=dispute_status_request,json=disputeStatusRequest,proto3,oneof"`
+}
+
+type Message_DisputeStatusResponse struct {
+	DisputeStatusResponse *DisputeStatusResponseMsg `protobuf:"bytes,19,opt,name=dispute_status_response,json=disputeStatusResponse,proto3,oneof"`
+}
+
+type Message_ListWatchedRequest struct {
+	ListWatchedRequest *ListWatchedRequestMsg `protobuf:"bytes,20,opt,name=list_watched_request,json=listWatchedRequest,proto3,oneof"`
+}
+
+type Message_ListWatchedResponse struct {
+	ListWatchedResponse *ListWatchedResponseMsg `protobuf:"bytes,21,opt,name=list_watched_response,json=listWatchedResponse,proto3,oneof"`
+}
+
 func (*Message_FundingRequest) isMessage_Msg() {}
 
 func (*Message_FundingResponse) isMessage_Msg() {}
@@ -171,6 +554,34 @@ func (*Message_ForceCloseResponse) isMessage_Msg() {}
 
 func (*Message_DisputeNotification) isMessage_Msg() {}
 
+func (*Message_HandshakeRequest) isMessage_Msg() {}
+
+func (*Message_HandshakeResponse) isMessage_Msg() {}
+
+func (*Message_StopWatchRequest) isMessage_Msg() {}
+
+func (*Message_StopWatchResponse) isMessage_Msg() {}
+
+func (*Message_FundingProgress) isMessage_Msg() {}
+
+func (*Message_WatchUpdate) isMessage_Msg() {}
+
+func (*Message_Ping) isMessage_Msg() {}
+
+func (*Message_Pong) isMessage_Msg() {}
+
+func (*Message_StatusRequest) isMessage_Msg() {}
+
+func (*Message_StatusResponse) isMessage_Msg() {}
+
+func (*Message_DisputeStatusRequest) isMessage_Msg() {}
+
+func (*Message_DisputeStatusResponse) isMessage_Msg() {}
+
+func (*Message_ListWatchedRequest) isMessage_Msg() {}
+
+func (*Message_ListWatchedResponse) isMessage_Msg() {}
+
 type FundingRequestMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -180,6 +591,10 @@ type FundingRequestMsg struct {
 	Params           *protobuf.Params   `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
 	InitialState     *protobuf.State    `protobuf:"bytes,3,opt,name=initial_state,json=initialState,proto3" json:"initial_state,omitempty"`
 	FundingAgreement *protobuf.Balances `protobuf:"bytes,4,opt,name=funding_agreement,json=fundingAgreement,proto3" json:"funding_agreement,omitempty"`
+	// Time in milliseconds after which the server gives up funding and
+	// answers with FUNDING_TIMEOUT. Zero for the server's funding timeout,
+	// which also bounds longer values.
+	TimeoutMs uint64 `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
 }
 
 func (x *FundingRequestMsg) Reset() {
@@ -242,6 +657,13 @@ func (x *FundingRequestMsg) GetFundingAgreement() *protobuf.Balances {
 	return nil
 }
 
+func (x *FundingRequestMsg) GetTimeoutMs() uint64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
 type FundingResponseMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -249,6 +671,9 @@ type FundingResponseMsg struct {
 
 	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 	Success   bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// Reason of the failure, empty on success.
+	Error string    `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Code  ErrorCode `protobuf:"varint,4,opt,name=code,proto3,enum=perunremote.ErrorCode" json:"code,omitempty"`
 }
 
 func (x *FundingResponseMsg) Reset() {
@@ -297,20 +722,36 @@ func (x *FundingResponseMsg) GetSuccess() bool {
 	return false
 }
 
-type WatchRequestMsg struct {
+func (x *FundingResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *FundingResponseMsg) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_OK
+}
+
+// Sent by the server while handling a FundingRequestMsg each time the funding
+// of an asset completed, before the FundingResponseMsg.
+type FundingProgressMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Participant uint32                `protobuf:"varint,1,opt,name=participant,proto3" json:"participant,omitempty"`
-	State       *protobuf.SignedState `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
-	// Signatures of the WithdrawalAuths needed for withdrawing assets on-chain
-	// (repeated for each asset_index):
-	WithdrawalAuths []*SignedWithdrawalAuth `protobuf:"bytes,3,rep,name=withdrawal_auths,json=withdrawalAuths,proto3" json:"withdrawal_auths,omitempty"`
+	ChannelId  []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	AssetIndex uint32 `protobuf:"varint,2,opt,name=asset_index,json=assetIndex,proto3" json:"asset_index,omitempty"`
+	// Number of funded assets, including this one.
+	Confirmed uint32 `protobuf:"varint,3,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	Total     uint32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
 }
 
-func (x *WatchRequestMsg) Reset() {
-	*x = WatchRequestMsg{}
+func (x *FundingProgressMsg) Reset() {
+	*x = FundingProgressMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_perun_remote_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -318,13 +759,13 @@ func (x *WatchRequestMsg) Reset() {
 	}
 }
 
-func (x *WatchRequestMsg) String() string {
+func (x *FundingProgressMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchRequestMsg) ProtoMessage() {}
+func (*FundingProgressMsg) ProtoMessage() {}
 
-func (x *WatchRequestMsg) ProtoReflect() protoreflect.Message {
+func (x *FundingProgressMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_perun_remote_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -336,51 +777,60 @@ func (x *WatchRequestMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchRequestMsg.ProtoReflect.Descriptor instead.
-func (*WatchRequestMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use FundingProgressMsg.ProtoReflect.Descriptor instead.
+func (*FundingProgressMsg) Descriptor() ([]byte, []int) {
 	return file_perun_remote_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *WatchRequestMsg) GetParticipant() uint32 {
+func (x *FundingProgressMsg) GetChannelId() []byte {
 	if x != nil {
-		return x.Participant
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *FundingProgressMsg) GetAssetIndex() uint32 {
+	if x != nil {
+		return x.AssetIndex
 	}
 	return 0
 }
 
-func (x *WatchRequestMsg) GetState() *protobuf.SignedState {
+func (x *FundingProgressMsg) GetConfirmed() uint32 {
 	if x != nil {
-		return x.State
+		return x.Confirmed
 	}
-	return nil
+	return 0
 }
 
-func (x *WatchRequestMsg) GetWithdrawalAuths() []*SignedWithdrawalAuth {
+func (x *FundingProgressMsg) GetTotal() uint32 {
 	if x != nil {
-		return x.WithdrawalAuths
+		return x.Total
 	}
-	return nil
+	return 0
 }
 
-// Data necessary to construct a WithdrawalAuth object for withdrawing funds
-// from the channel. State, Params and the asset_index=index_in_list are
-// additionally needed.
-type SignedWithdrawalAuth struct {
+type WatchRequestMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Content of the on-chain WithdrawalAuth object (Ethereum):
-	//     bytes32 channelID = state.id;
-	//     address participant = params.parts[i];
-	//     address payable receiver; // On-chain address specified by application
-	//     uint256 amount = state.allocation.balances[asset_index].balances[i];
-	Sig      []byte `protobuf:"bytes,1,opt,name=sig,proto3" json:"sig,omitempty"`
-	Receiver []byte `protobuf:"bytes,2,opt,name=receiver,proto3" json:"receiver,omitempty"`
+	Participant uint32                `protobuf:"varint,1,opt,name=participant,proto3" json:"participant,omitempty"`
+	State       *protobuf.SignedState `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// Signatures of the WithdrawalAuths needed for withdrawing assets on-chain
+	// (repeated for each asset_index):
+	WithdrawalAuths []*SignedWithdrawalAuth `protobuf:"bytes,3,rep,name=withdrawal_auths,json=withdrawalAuths,proto3" json:"withdrawal_auths,omitempty"`
+	// Optional on-chain address receiving the withdrawn funds. If set, the
+	// receivers of all withdrawal auths have to be empty or equal to it.
+	Receiver []byte      `protobuf:"bytes,4,opt,name=receiver,proto3" json:"receiver,omitempty"`
+	Type     ChannelType `protobuf:"varint,5,opt,name=type,proto3,enum=perunremote.ChannelType" json:"type,omitempty"`
+	// ID of the parent channel of a sub-channel, which has to be watched
+	// already. Funds of sub-channels are withdrawn with the parent.
+	Parent []byte `protobuf:"bytes,6,opt,name=parent,proto3" json:"parent,omitempty"`
 }
 
-func (x *SignedWithdrawalAuth) Reset() {
-	*x = SignedWithdrawalAuth{}
+func (x *WatchRequestMsg) Reset() {
+	*x = WatchRequestMsg{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_perun_remote_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -388,13 +838,13 @@ func (x *SignedWithdrawalAuth) Reset() {
 	}
 }
 
-func (x *SignedWithdrawalAuth) String() string {
+func (x *WatchRequestMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignedWithdrawalAuth) ProtoMessage() {}
+func (*WatchRequestMsg) ProtoMessage() {}
 
-func (x *SignedWithdrawalAuth) ProtoReflect() protoreflect.Message {
+func (x *WatchRequestMsg) ProtoReflect() protoreflect.Message {
 	mi := &file_perun_remote_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -406,11 +856,162 @@ func (x *SignedWithdrawalAuth) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignedWithdrawalAuth.ProtoReflect.Descriptor instead.
-func (*SignedWithdrawalAuth) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchRequestMsg.ProtoReflect.Descriptor instead.
+func (*WatchRequestMsg) Descriptor() ([]byte, []int) {
 	return file_perun_remote_proto_rawDescGZIP(), []int{4}
 }
 
+func (x *WatchRequestMsg) GetParticipant() uint32 {
+	if x != nil {
+		return x.Participant
+	}
+	return 0
+}
+
+func (x *WatchRequestMsg) GetState() *protobuf.SignedState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *WatchRequestMsg) GetWithdrawalAuths() []*SignedWithdrawalAuth {
+	if x != nil {
+		return x.WithdrawalAuths
+	}
+	return nil
+}
+
+func (x *WatchRequestMsg) GetReceiver() []byte {
+	if x != nil {
+		return x.Receiver
+	}
+	return nil
+}
+
+func (x *WatchRequestMsg) GetType() ChannelType {
+	if x != nil {
+		return x.Type
+	}
+	return ChannelType_LEDGER
+}
+
+func (x *WatchRequestMsg) GetParent() []byte {
+	if x != nil {
+		return x.Parent
+	}
+	return nil
+}
+
+// Updates the state of a channel which is already watched, answered by a
+// WatchResponseMsg. Unlike a WatchRequestMsg, it is rejected for channels
+// which are not watched.
+type WatchUpdateMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request *WatchRequestMsg `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	// Version the client last sent for the channel, the version of the
+	// updated state has to be greater.
+	BaseVersion uint64 `protobuf:"varint,2,opt,name=base_version,json=baseVersion,proto3" json:"base_version,omitempty"`
+}
+
+func (x *WatchUpdateMsg) Reset() {
+	*x = WatchUpdateMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchUpdateMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUpdateMsg) ProtoMessage() {}
+
+func (x *WatchUpdateMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUpdateMsg.ProtoReflect.Descriptor instead.
+func (*WatchUpdateMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WatchUpdateMsg) GetRequest() *WatchRequestMsg {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *WatchUpdateMsg) GetBaseVersion() uint64 {
+	if x != nil {
+		return x.BaseVersion
+	}
+	return 0
+}
+
+// Data necessary to construct a WithdrawalAuth object for withdrawing funds
+// from the channel. State, Params and the asset_index=index_in_list are
+// additionally needed.
+type SignedWithdrawalAuth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Content of the on-chain WithdrawalAuth object (Ethereum):
+	//     bytes32 channelID = state.id;
+	//     address participant = params.parts[i];
+	//     address payable receiver; // On-chain address specified by application
+	//     uint256 amount = state.allocation.balances[asset_index].balances[i];
+	Sig      []byte `protobuf:"bytes,1,opt,name=sig,proto3" json:"sig,omitempty"`
+	Receiver []byte `protobuf:"bytes,2,opt,name=receiver,proto3" json:"receiver,omitempty"`
+}
+
+func (x *SignedWithdrawalAuth) Reset() {
+	*x = SignedWithdrawalAuth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignedWithdrawalAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedWithdrawalAuth) ProtoMessage() {}
+
+func (x *SignedWithdrawalAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedWithdrawalAuth.ProtoReflect.Descriptor instead.
+func (*SignedWithdrawalAuth) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{6}
+}
+
 func (x *SignedWithdrawalAuth) GetSig() []byte {
 	if x != nil {
 		return x.Sig
@@ -433,12 +1034,15 @@ type WatchResponseMsg struct {
 	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 	Version   uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	Success   bool   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// Reason of the failure, empty on success.
+	Error string    `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Code  ErrorCode `protobuf:"varint,5,opt,name=code,proto3,enum=perunremote.ErrorCode" json:"code,omitempty"`
 }
 
 func (x *WatchResponseMsg) Reset() {
 	*x = WatchResponseMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_perun_remote_proto_msgTypes[5]
+		mi := &file_perun_remote_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -451,7 +1055,7 @@ func (x *WatchResponseMsg) String() string {
 func (*WatchResponseMsg) ProtoMessage() {}
 
 func (x *WatchResponseMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_perun_remote_proto_msgTypes[5]
+	mi := &file_perun_remote_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +1068,7 @@ func (x *WatchResponseMsg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchResponseMsg.ProtoReflect.Descriptor instead.
 func (*WatchResponseMsg) Descriptor() ([]byte, []int) {
-	return file_perun_remote_proto_rawDescGZIP(), []int{5}
+	return file_perun_remote_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *WatchResponseMsg) GetChannelId() []byte {
@@ -474,52 +1078,838 @@ func (x *WatchResponseMsg) GetChannelId() []byte {
 	return nil
 }
 
-func (x *WatchResponseMsg) GetVersion() uint64 {
+func (x *WatchResponseMsg) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *WatchResponseMsg) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *WatchResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WatchResponseMsg) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_OK
+}
+
+type ForceCloseRequestMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// Implicitly optional (messages have explicit presence). I'd love to mark
+	// it explicitly as optional to indicate that it is intentionally optional,
+	// but that requires protoc version 3.15 which the CI does not have (version
+	// on Ubuntu: 3.12.4). We could either enable the experimental flag or
+	// remove the optional flag. Since the flag doesn't make a real difference
+	// here due to the "message" type, I've removed it.
+	Latest *WatchRequestMsg `protobuf:"bytes,2,opt,name=latest,proto3" json:"latest,omitempty"`
+}
+
+func (x *ForceCloseRequestMsg) Reset() {
+	*x = ForceCloseRequestMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForceCloseRequestMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCloseRequestMsg) ProtoMessage() {}
+
+func (x *ForceCloseRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCloseRequestMsg.ProtoReflect.Descriptor instead.
+func (*ForceCloseRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ForceCloseRequestMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *ForceCloseRequestMsg) GetLatest() *WatchRequestMsg {
+	if x != nil {
+		return x.Latest
+	}
+	return nil
+}
+
+type ForceCloseResponseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Success   bool   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// Reason of the failure, empty on success.
+	Error string    `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Code  ErrorCode `protobuf:"varint,5,opt,name=code,proto3,enum=perunremote.ErrorCode" json:"code,omitempty"`
+}
+
+func (x *ForceCloseResponseMsg) Reset() {
+	*x = ForceCloseResponseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForceCloseResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceCloseResponseMsg) ProtoMessage() {}
+
+func (x *ForceCloseResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceCloseResponseMsg.ProtoReflect.Descriptor instead.
+func (*ForceCloseResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ForceCloseResponseMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *ForceCloseResponseMsg) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ForceCloseResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ForceCloseResponseMsg) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_OK
+}
+
+// Sent by the server when a dispute of a watched channel is detected on-chain.
+type DisputeNotification struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	// Version of the state registered or progressed to on-chain.
+	Version uint64       `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Event   DisputeEvent `protobuf:"varint,3,opt,name=event,proto3,enum=perunremote.DisputeEvent" json:"event,omitempty"`
+}
+
+func (x *DisputeNotification) Reset() {
+	*x = DisputeNotification{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisputeNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeNotification) ProtoMessage() {}
+
+func (x *DisputeNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeNotification.ProtoReflect.Descriptor instead.
+func (*DisputeNotification) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DisputeNotification) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *DisputeNotification) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DisputeNotification) GetEvent() DisputeEvent {
+	if x != nil {
+		return x.Event
+	}
+	return DisputeEvent_REGISTERED
+}
+
+// Sent by the client when it no longer needs the channel to be watched, e.g.
+// after settling it.
+type StopWatchRequestMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+}
+
+func (x *StopWatchRequestMsg) Reset() {
+	*x = StopWatchRequestMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopWatchRequestMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopWatchRequestMsg) ProtoMessage() {}
+
+func (x *StopWatchRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopWatchRequestMsg.ProtoReflect.Descriptor instead.
+func (*StopWatchRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StopWatchRequestMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+type StopWatchResponseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Success   bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// Reason of the failure, empty on success.
+	Error string    `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Code  ErrorCode `protobuf:"varint,4,opt,name=code,proto3,enum=perunremote.ErrorCode" json:"code,omitempty"`
+}
+
+func (x *StopWatchResponseMsg) Reset() {
+	*x = StopWatchResponseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StopWatchResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopWatchResponseMsg) ProtoMessage() {}
+
+func (x *StopWatchResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopWatchResponseMsg.ProtoReflect.Descriptor instead.
+func (*StopWatchResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StopWatchResponseMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *StopWatchResponseMsg) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *StopWatchResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *StopWatchResponseMsg) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_OK
+}
+
+// Sent by the client as the first message on a connection.
+type HandshakeRequestMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *HandshakeRequestMsg) Reset() {
+	*x = HandshakeRequestMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeRequestMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeRequestMsg) ProtoMessage() {}
+
+func (x *HandshakeRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeRequestMsg.ProtoReflect.Descriptor instead.
+func (*HandshakeRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HandshakeRequestMsg) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Answer to the handshake. If the versions are incompatible, success is false,
+// error describes the problem and the server closes the connection.
+type HandshakeResponseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error   string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *HandshakeResponseMsg) Reset() {
+	*x = HandshakeResponseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HandshakeResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandshakeResponseMsg) ProtoMessage() {}
+
+func (x *HandshakeResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandshakeResponseMsg.ProtoReflect.Descriptor instead.
+func (*HandshakeResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HandshakeResponseMsg) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *HandshakeResponseMsg) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *HandshakeResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Keepalive, may be sent by both sides at any time after the handshake. The
+// receiver answers with a PongMsg carrying the same nonce.
+type PingMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *PingMsg) Reset() {
+	*x = PingMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingMsg) ProtoMessage() {}
+
+func (x *PingMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingMsg.ProtoReflect.Descriptor instead.
+func (*PingMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PingMsg) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+type PongMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+}
+
+func (x *PongMsg) Reset() {
+	*x = PongMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PongMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PongMsg) ProtoMessage() {}
+
+func (x *PongMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PongMsg.ProtoReflect.Descriptor instead.
+func (*PongMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PongMsg) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+// Health check, answered by a StatusResponseMsg. It may be sent at any time
+// after the handshake or instead of it, in which case the server closes the
+// connection after answering.
+type StatusRequestMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StatusRequestMsg) Reset() {
+	*x = StatusRequestMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusRequestMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequestMsg) ProtoMessage() {}
+
+func (x *StatusRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequestMsg.ProtoReflect.Descriptor instead.
+func (*StatusRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{17}
+}
+
+type StatusResponseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProtocolVersion uint32 `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	WatchedChannels uint64 `protobuf:"varint,2,opt,name=watched_channels,json=watchedChannels,proto3" json:"watched_channels,omitempty"`
+	UptimeSeconds   uint64 `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+}
+
+func (x *StatusResponseMsg) Reset() {
+	*x = StatusResponseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponseMsg) ProtoMessage() {}
+
+func (x *StatusResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponseMsg.ProtoReflect.Descriptor instead.
+func (*StatusResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StatusResponseMsg) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *StatusResponseMsg) GetWatchedChannels() uint64 {
+	if x != nil {
+		return x.WatchedChannels
+	}
+	return 0
+}
+
+func (x *StatusResponseMsg) GetUptimeSeconds() uint64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+// Asks for the on-chain dispute state of a channel, which does not need to be
+// watched. Answered by a DisputeStatusResponseMsg.
+type DisputeStatusRequestMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+}
+
+func (x *DisputeStatusRequestMsg) Reset() {
+	*x = DisputeStatusRequestMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisputeStatusRequestMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeStatusRequestMsg) ProtoMessage() {}
+
+func (x *DisputeStatusRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeStatusRequestMsg.ProtoReflect.Descriptor instead.
+func (*DisputeStatusRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DisputeStatusRequestMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+type DisputeStatusResponseMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Success   bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// Reason of the failure, empty on success.
+	Error string       `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Code  ErrorCode    `protobuf:"varint,4,opt,name=code,proto3,enum=perunremote.ErrorCode" json:"code,omitempty"`
+	Phase DisputePhase `protobuf:"varint,5,opt,name=phase,proto3,enum=perunremote.DisputePhase" json:"phase,omitempty"`
+	// Version of the state registered on-chain, zero without a dispute.
+	Version uint64 `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Whether the timeout of the current phase has elapsed.
+	TimeoutElapsed bool `protobuf:"varint,7,opt,name=timeout_elapsed,json=timeoutElapsed,proto3" json:"timeout_elapsed,omitempty"`
+}
+
+func (x *DisputeStatusResponseMsg) Reset() {
+	*x = DisputeStatusResponseMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_perun_remote_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisputeStatusResponseMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeStatusResponseMsg) ProtoMessage() {}
+
+func (x *DisputeStatusResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeStatusResponseMsg.ProtoReflect.Descriptor instead.
+func (*DisputeStatusResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DisputeStatusResponseMsg) GetChannelId() []byte {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+func (x *DisputeStatusResponseMsg) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DisputeStatusResponseMsg) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DisputeStatusResponseMsg) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_OK
+}
+
+func (x *DisputeStatusResponseMsg) GetPhase() DisputePhase {
+	if x != nil {
+		return x.Phase
+	}
+	return DisputePhase_NO_DISPUTE
+}
+
+func (x *DisputeStatusResponseMsg) GetVersion() uint64 {
 	if x != nil {
 		return x.Version
 	}
 	return 0
 }
 
-func (x *WatchResponseMsg) GetSuccess() bool {
+func (x *DisputeStatusResponseMsg) GetTimeoutElapsed() bool {
 	if x != nil {
-		return x.Success
+		return x.TimeoutElapsed
 	}
 	return false
 }
 
-type ForceCloseRequestMsg struct {
+// Asks for the channels the server is watching, e.g. for the client to
+// reconcile its channels with them. Answered by a ListWatchedResponseMsg.
+type ListWatchedRequestMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
-	// Implicitly optional (messages have explicit presence). I'd love to mark
-	// it explicitly as optional to indicate that it is intentionally optional,
-	// but that requires protoc version 3.15 which the CI does not have (version
-	// on Ubuntu: 3.12.4). We could either enable the experimental flag or
-	// remove the optional flag. Since the flag doesn't make a real difference
-	// here due to the "message" type, I've removed it.
-	Latest *WatchRequestMsg `protobuf:"bytes,2,opt,name=latest,proto3" json:"latest,omitempty"`
 }
 
-func (x *ForceCloseRequestMsg) Reset() {
-	*x = ForceCloseRequestMsg{}
+func (x *ListWatchedRequestMsg) Reset() {
+	*x = ListWatchedRequestMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_perun_remote_proto_msgTypes[6]
+		mi := &file_perun_remote_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForceCloseRequestMsg) String() string {
+func (x *ListWatchedRequestMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForceCloseRequestMsg) ProtoMessage() {}
+func (*ListWatchedRequestMsg) ProtoMessage() {}
 
-func (x *ForceCloseRequestMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_perun_remote_proto_msgTypes[6]
+func (x *ListWatchedRequestMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,51 +1920,37 @@ func (x *ForceCloseRequestMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForceCloseRequestMsg.ProtoReflect.Descriptor instead.
-func (*ForceCloseRequestMsg) Descriptor() ([]byte, []int) {
-	return file_perun_remote_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *ForceCloseRequestMsg) GetChannelId() []byte {
-	if x != nil {
-		return x.ChannelId
-	}
-	return nil
-}
-
-func (x *ForceCloseRequestMsg) GetLatest() *WatchRequestMsg {
-	if x != nil {
-		return x.Latest
-	}
-	return nil
+// Deprecated: Use ListWatchedRequestMsg.ProtoReflect.Descriptor instead.
+func (*ListWatchedRequestMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{21}
 }
 
-type ForceCloseResponseMsg struct {
+type ListWatchedResponseMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
-	Success   bool   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// Sorted by channel ID.
+	Channels []*WatchedChannel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
 }
 
-func (x *ForceCloseResponseMsg) Reset() {
-	*x = ForceCloseResponseMsg{}
+func (x *ListWatchedResponseMsg) Reset() {
+	*x = ListWatchedResponseMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_perun_remote_proto_msgTypes[7]
+		mi := &file_perun_remote_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForceCloseResponseMsg) String() string {
+func (x *ListWatchedResponseMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForceCloseResponseMsg) ProtoMessage() {}
+func (*ListWatchedResponseMsg) ProtoMessage() {}
 
-func (x *ForceCloseResponseMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_perun_remote_proto_msgTypes[7]
+func (x *ListWatchedResponseMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -585,50 +1961,48 @@ func (x *ForceCloseResponseMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForceCloseResponseMsg.ProtoReflect.Descriptor instead.
-func (*ForceCloseResponseMsg) Descriptor() ([]byte, []int) {
-	return file_perun_remote_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ListWatchedResponseMsg.ProtoReflect.Descriptor instead.
+func (*ListWatchedResponseMsg) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ForceCloseResponseMsg) GetChannelId() []byte {
+func (x *ListWatchedResponseMsg) GetChannels() []*WatchedChannel {
 	if x != nil {
-		return x.ChannelId
+		return x.Channels
 	}
 	return nil
 }
 
-func (x *ForceCloseResponseMsg) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-type DisputeNotification struct {
+type WatchedChannel struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChannelId []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	ChannelId   []byte `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Participant uint32 `protobuf:"varint,2,opt,name=participant,proto3" json:"participant,omitempty"`
+	// Version of the latest watched state.
+	Version uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Whether the server saw a dispute of the channel on-chain.
+	Disputed bool `protobuf:"varint,4,opt,name=disputed,proto3" json:"disputed,omitempty"`
 }
 
-func (x *DisputeNotification) Reset() {
-	*x = DisputeNotification{}
+func (x *WatchedChannel) Reset() {
+	*x = WatchedChannel{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_perun_remote_proto_msgTypes[8]
+		mi := &file_perun_remote_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DisputeNotification) String() string {
+func (x *WatchedChannel) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DisputeNotification) ProtoMessage() {}
+func (*WatchedChannel) ProtoMessage() {}
 
-func (x *DisputeNotification) ProtoReflect() protoreflect.Message {
-	mi := &file_perun_remote_proto_msgTypes[8]
+func (x *WatchedChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_perun_remote_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -639,24 +2013,45 @@ func (x *DisputeNotification) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DisputeNotification.ProtoReflect.Descriptor instead.
-func (*DisputeNotification) Descriptor() ([]byte, []int) {
-	return file_perun_remote_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use WatchedChannel.ProtoReflect.Descriptor instead.
+func (*WatchedChannel) Descriptor() ([]byte, []int) {
+	return file_perun_remote_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *DisputeNotification) GetChannelId() []byte {
+func (x *WatchedChannel) GetChannelId() []byte {
 	if x != nil {
 		return x.ChannelId
 	}
 	return nil
 }
 
+func (x *WatchedChannel) GetParticipant() uint32 {
+	if x != nil {
+		return x.Participant
+	}
+	return 0
+}
+
+func (x *WatchedChannel) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *WatchedChannel) GetDisputed() bool {
+	if x != nil {
+		return x.Disputed
+	}
+	return false
+}
+
 var File_perun_remote_proto protoreflect.FileDescriptor
 
 var file_perun_remote_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x1a, 0x0a, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xba, 0x04,
+	0x65, 0x1a, 0x0a, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf3, 0x0c,
 	0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x0f, 0x66, 0x75, 0x6e,
 	0x64, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
@@ -692,64 +2087,265 @@ var file_perun_remote_proto_rawDesc = []byte{
 	0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
 	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x13,
 	0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x42, 0x05, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x22, 0xd9, 0x01, 0x0a, 0x11, 0x46,
-	0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
-	0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61,
-	0x6e, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x35, 0x0a,
-	0x0d, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65,
-	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x40, 0x0a, 0x11, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f,
-	0x61, 0x67, 0x72, 0x65, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x13, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6c, 0x61,
-	0x6e, 0x63, 0x65, 0x73, 0x52, 0x10, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x67, 0x72,
-	0x65, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x4d, 0x0a, 0x12, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e,
-	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73,
-	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0xaf, 0x01, 0x0a, 0x0f, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x69, 0x6f, 0x6e, 0x12, 0x4f, 0x0a, 0x11, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65,
+	0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x48, 0x61, 0x6e,
+	0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x48, 0x00, 0x52, 0x10, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x52, 0x0a, 0x12, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b,
+	0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x48,
+	0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x11, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x12, 0x73, 0x74, 0x6f, 0x70,
+	0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x10, 0x73, 0x74, 0x6f, 0x70, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x53, 0x0a, 0x13, 0x73, 0x74,
+	0x6f, 0x70, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x11, 0x73, 0x74,
+	0x6f, 0x70, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4c, 0x0a, 0x10, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x65, 0x72, 0x75,
+	0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0f, 0x66, 0x75,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x40, 0x0a,
+	0x0c, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x73, 0x67,
+	0x48, 0x00, 0x52, 0x0b, 0x77, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x2a, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x50, 0x69, 0x6e, 0x67,
+	0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x04, 0x70,
+	0x6f, 0x6e, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x65, 0x72, 0x75,
+	0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x50, 0x6f, 0x6e, 0x67, 0x4d, 0x73, 0x67, 0x48,
+	0x00, 0x52, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x12, 0x46, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x48, 0x00,
+	0x52, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x49, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x16, 0x64, 0x69,
+	0x73, 0x70, 0x75, 0x74, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x65, 0x72,
+	0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x48, 0x00, 0x52, 0x14, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5f, 0x0a, 0x17, 0x64, 0x69, 0x73, 0x70,
+	0x75, 0x74, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x65, 0x72, 0x75,
+	0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67,
+	0x48, 0x00, 0x52, 0x15, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x14, 0x6c, 0x69, 0x73,
+	0x74, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x12, 0x6c,
+	0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x59, 0x0a, 0x15, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x23, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x13, 0x6c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x05, 0x0a, 0x03,
+	0x6d, 0x73, 0x67, 0x22, 0xf8, 0x01, 0x0a, 0x11, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72,
 	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
-	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x72,
-	0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x4c, 0x0a, 0x10, 0x77, 0x69, 0x74,
-	0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77,
-	0x61, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x52, 0x0f, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77,
-	0x61, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x73, 0x22, 0x44, 0x0a, 0x14, 0x53, 0x69, 0x67, 0x6e, 0x65,
-	0x64, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x12,
-	0x10, 0x0a, 0x03, 0x73, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x73, 0x69,
-	0x67, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x22, 0x65, 0x0a,
-	0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73,
-	0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
-	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x22, 0x6b, 0x0a, 0x14, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x43, 0x6c, 0x6f,
-	0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x65,
+	0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x35, 0x0a, 0x0d, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x70, 0x65, 0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52,
+	0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x40, 0x0a,
+	0x11, 0x66, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x67, 0x72, 0x65, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x10, 0x66,
+	0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x41, 0x67, 0x72, 0x65, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x22, 0x8f,
+	0x01, 0x0a, 0x12, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x22, 0x88, 0x01, 0x0a, 0x12, 0x46, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x72, 0x6d, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x91, 0x02, 0x0a, 0x0f,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12,
+	0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e,
+	0x74, 0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x4c, 0x0a, 0x10, 0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x5f, 0x61, 0x75,
+	0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x65, 0x72, 0x75,
+	0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x57, 0x69,
+	0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x52, 0x0f, 0x77, 0x69,
+	0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x73, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x65, 0x6e,
+	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x22,
+	0x6b, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x73,
+	0x67, 0x12, 0x36, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x73,
+	0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x44, 0x0a, 0x14,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x61, 0x6c,
+	0x41, 0x75, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x03, 0x73, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x72, 0x22, 0xa7, 0x01, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x2a, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x6b, 0x0a, 0x14,
+	0x46, 0x6f, 0x72, 0x63, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x06, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73,
+	0x67, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x22, 0x92, 0x01, 0x0a, 0x15, 0x46, 0x6f,
+	0x72, 0x63, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x7f,
+	0x0a, 0x13, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e,
+	0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x73, 0x70,
+	0x75, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0x34, 0x0a, 0x13, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x91, 0x01, 0x0a, 0x14, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x65,
+	0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x2f, 0x0a, 0x13, 0x48, 0x61, 0x6e,
+	0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x60, 0x0a, 0x14, 0x48, 0x61,
+	0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d,
+	0x73, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x1f, 0x0a, 0x07,
+	0x50, 0x69, 0x6e, 0x67, 0x4d, 0x73, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x1f, 0x0a,
+	0x07, 0x50, 0x6f, 0x6e, 0x67, 0x4d, 0x73, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0x12,
+	0x0a, 0x10, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x73, 0x67, 0x22, 0x90, 0x01, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x77,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x38, 0x0a, 0x17, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22,
+	0x89, 0x02, 0x0a, 0x18, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a,
 	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x06, 0x6c,
-	0x61, 0x74, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x65,
-	0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x65, 0x73,
-	0x74, 0x22, 0x50, 0x0a, 0x15, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x22, 0x34, 0x0a, 0x13, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x4e, 0x6f,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68,
+	0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x75,
+	0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x44, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65, 0x50, 0x68, 0x61, 0x73,
+	0x65, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x65, 0x6c,
+	0x61, 0x70, 0x73, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x45, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x4c,
+	0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x4d, 0x73, 0x67, 0x22, 0x51, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x73, 0x67, 0x12, 0x37,
+	0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x70, 0x65, 0x72, 0x75, 0x6e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0x87, 0x01, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68,
 	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72,
+	0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x63, 0x69, 0x70, 0x61, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x70, 0x75, 0x74, 0x65,
+	0x64, 0x2a, 0x2f, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0a, 0x0a, 0x06, 0x4c, 0x45, 0x44, 0x47, 0x45, 0x52, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
+	0x53, 0x55, 0x42, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x49, 0x52, 0x54, 0x55, 0x41, 0x4c,
+	0x10, 0x02, 0x2a, 0xa4, 0x01, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41,
+	0x4c, 0x49, 0x44, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x01, 0x12, 0x13, 0x0a,
+	0x0f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c,
+	0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x55, 0x54, 0x44, 0x41, 0x54, 0x45, 0x44, 0x5f, 0x56,
+	0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x55, 0x4e, 0x44,
+	0x49, 0x4e, 0x47, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e,
+	0x44, 0x49, 0x53, 0x50, 0x55, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x05,
+	0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x06, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x55, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f,
+	0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x07, 0x2a, 0x2e, 0x0a, 0x0c, 0x44, 0x69, 0x73,
+	0x70, 0x75, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x0a, 0x52, 0x45, 0x47,
+	0x49, 0x53, 0x54, 0x45, 0x52, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x52, 0x4f,
+	0x47, 0x52, 0x45, 0x53, 0x53, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x4a, 0x0a, 0x0c, 0x44, 0x69, 0x73,
+	0x70, 0x75, 0x74, 0x65, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x4e, 0x4f, 0x5f,
+	0x44, 0x49, 0x53, 0x50, 0x55, 0x54, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x49, 0x53,
+	0x50, 0x55, 0x54, 0x45, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f,
+	0x45, 0x58, 0x45, 0x43, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4e, 0x43, 0x4c, 0x55,
+	0x44, 0x45, 0x44, 0x10, 0x03, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -764,41 +2360,85 @@ func file_perun_remote_proto_rawDescGZIP() []byte {
 	return file_perun_remote_proto_rawDescData
 }
 
-var file_perun_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_perun_remote_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_perun_remote_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_perun_remote_proto_goTypes = []interface{}{
-	(*Message)(nil),               // 0: perunremote.Message
-	(*FundingRequestMsg)(nil),     // 1: perunremote.FundingRequestMsg
-	(*FundingResponseMsg)(nil),    // 2: perunremote.FundingResponseMsg
-	(*WatchRequestMsg)(nil),       // 3: perunremote.WatchRequestMsg
-	(*SignedWithdrawalAuth)(nil),  // 4: perunremote.SignedWithdrawalAuth
-	(*WatchResponseMsg)(nil),      // 5: perunremote.WatchResponseMsg
-	(*ForceCloseRequestMsg)(nil),  // 6: perunremote.ForceCloseRequestMsg
-	(*ForceCloseResponseMsg)(nil), // 7: perunremote.ForceCloseResponseMsg
-	(*DisputeNotification)(nil),   // 8: perunremote.DisputeNotification
-	(*protobuf.Params)(nil),       // 9: perunwire.Params
-	(*protobuf.State)(nil),        // 10: perunwire.State
-	(*protobuf.Balances)(nil),     // 11: perunwire.Balances
-	(*protobuf.SignedState)(nil),  // 12: perunwire.SignedState
+	(ChannelType)(0),                 // 0: perunremote.ChannelType
+	(ErrorCode)(0),                   // 1: perunremote.ErrorCode
+	(DisputeEvent)(0),                // 2: perunremote.DisputeEvent
+	(DisputePhase)(0),                // 3: perunremote.DisputePhase
+	(*Message)(nil),                  // 4: perunremote.Message
+	(*FundingRequestMsg)(nil),        // 5: perunremote.FundingRequestMsg
+	(*FundingResponseMsg)(nil),       // 6: perunremote.FundingResponseMsg
+	(*FundingProgressMsg)(nil),       // 7: perunremote.FundingProgressMsg
+	(*WatchRequestMsg)(nil),          // 8: perunremote.WatchRequestMsg
+	(*WatchUpdateMsg)(nil),           // 9: perunremote.WatchUpdateMsg
+	(*SignedWithdrawalAuth)(nil),     // 10: perunremote.SignedWithdrawalAuth
+	(*WatchResponseMsg)(nil),         // 11: perunremote.WatchResponseMsg
+	(*ForceCloseRequestMsg)(nil),     // 12: perunremote.ForceCloseRequestMsg
+	(*ForceCloseResponseMsg)(nil),    // 13: perunremote.ForceCloseResponseMsg
+	(*DisputeNotification)(nil),      // 14: perunremote.DisputeNotification
+	(*StopWatchRequestMsg)(nil),      // 15: perunremote.StopWatchRequestMsg
+	(*StopWatchResponseMsg)(nil),     // 16: perunremote.StopWatchResponseMsg
+	(*HandshakeRequestMsg)(nil),      // 17: perunremote.HandshakeRequestMsg
+	(*HandshakeResponseMsg)(nil),     // 18: perunremote.HandshakeResponseMsg
+	(*PingMsg)(nil),                  // 19: perunremote.PingMsg
+	(*PongMsg)(nil),                  // 20: perunremote.PongMsg
+	(*StatusRequestMsg)(nil),         // 21: perunremote.StatusRequestMsg
+	(*StatusResponseMsg)(nil),        // 22: perunremote.StatusResponseMsg
+	(*DisputeStatusRequestMsg)(nil),  // 23: perunremote.DisputeStatusRequestMsg
+	(*DisputeStatusResponseMsg)(nil), // 24: perunremote.DisputeStatusResponseMsg
+	(*ListWatchedRequestMsg)(nil),    // 25: perunremote.ListWatchedRequestMsg
+	(*ListWatchedResponseMsg)(nil),   // 26: perunremote.ListWatchedResponseMsg
+	(*WatchedChannel)(nil),           // 27: perunremote.WatchedChannel
+	(*protobuf.Params)(nil),          // 28: perunwire.Params
+	(*protobuf.State)(nil),           // 29: perunwire.State
+	(*protobuf.Balances)(nil),        // 30: perunwire.Balances
+	(*protobuf.SignedState)(nil),     // 31: perunwire.SignedState
 }
 var file_perun_remote_proto_depIdxs = []int32{
-	1,  // 0: perunremote.Message.funding_request:type_name -> perunremote.FundingRequestMsg
-	2,  // 1: perunremote.Message.funding_response:type_name -> perunremote.FundingResponseMsg
-	3,  // 2: perunremote.Message.watch_request:type_name -> perunremote.WatchRequestMsg
-	5,  // 3: perunremote.Message.watch_response:type_name -> perunremote.WatchResponseMsg
-	6,  // 4: perunremote.Message.force_close_request:type_name -> perunremote.ForceCloseRequestMsg
-	7,  // 5: perunremote.Message.force_close_response:type_name -> perunremote.ForceCloseResponseMsg
-	8,  // 6: perunremote.Message.dispute_notification:type_name -> perunremote.DisputeNotification
-	9,  // 7: perunremote.FundingRequestMsg.params:type_name -> perunwire.Params
-	10, // 8: perunremote.FundingRequestMsg.initial_state:type_name -> perunwire.State
-	11, // 9: perunremote.FundingRequestMsg.funding_agreement:type_name -> perunwire.Balances
-	12, // 10: perunremote.WatchRequestMsg.state:type_name -> perunwire.SignedState
-	4,  // 11: perunremote.WatchRequestMsg.withdrawal_auths:type_name -> perunremote.SignedWithdrawalAuth
-	3,  // 12: perunremote.ForceCloseRequestMsg.latest:type_name -> perunremote.WatchRequestMsg
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	5,  // 0: perunremote.Message.funding_request:type_name -> perunremote.FundingRequestMsg
+	6,  // 1: perunremote.Message.funding_response:type_name -> perunremote.FundingResponseMsg
+	8,  // 2: perunremote.Message.watch_request:type_name -> perunremote.WatchRequestMsg
+	11, // 3: perunremote.Message.watch_response:type_name -> perunremote.WatchResponseMsg
+	12, // 4: perunremote.Message.force_close_request:type_name -> perunremote.ForceCloseRequestMsg
+	13, // 5: perunremote.Message.force_close_response:type_name -> perunremote.ForceCloseResponseMsg
+	14, // 6: perunremote.Message.dispute_notification:type_name -> perunremote.DisputeNotification
+	17, // 7: perunremote.Message.handshake_request:type_name -> perunremote.HandshakeRequestMsg
+	18, // 8: perunremote.Message.handshake_response:type_name -> perunremote.HandshakeResponseMsg
+	15, // 9: perunremote.Message.stop_watch_request:type_name -> perunremote.StopWatchRequestMsg
+	16, // 10: perunremote.Message.stop_watch_response:type_name -> perunremote.StopWatchResponseMsg
+	7,  // 11: perunremote.Message.funding_progress:type_name -> perunremote.FundingProgressMsg
+	9,  // 12: perunremote.Message.watch_update:type_name -> perunremote.WatchUpdateMsg
+	19, // 13: perunremote.Message.ping:type_name -> perunremote.PingMsg
+	20, // 14: perunremote.Message.pong:type_name -> perunremote.PongMsg
+	21, // 15: perunremote.Message.status_request:type_name -> perunremote.StatusRequestMsg
+	22, // 16: perunremote.Message.status_response:type_name -> perunremote.StatusResponseMsg
+	23, // 17: perunremote.Message.dispute_status_request:type_name -> perunremote.DisputeStatusRequestMsg
+	24, // 18: perunremote.Message.dispute_status_response:type_name -> perunremote.DisputeStatusResponseMsg
+	25, // 19: perunremote.Message.list_watched_request:type_name -> perunremote.ListWatchedRequestMsg
+	26, // 20: perunremote.Message.list_watched_response:type_name -> perunremote.ListWatchedResponseMsg
+	28, // 21: perunremote.FundingRequestMsg.params:type_name -> perunwire.Params
+	29, // 22: perunremote.FundingRequestMsg.initial_state:type_name -> perunwire.State
+	30, // 23: perunremote.FundingRequestMsg.funding_agreement:type_name -> perunwire.Balances
+	1,  // 24: perunremote.FundingResponseMsg.code:type_name -> perunremote.ErrorCode
+	31, // 25: perunremote.WatchRequestMsg.state:type_name -> perunwire.SignedState
+	10, // 26: perunremote.WatchRequestMsg.withdrawal_auths:type_name -> perunremote.SignedWithdrawalAuth
+	0,  // 27: perunremote.WatchRequestMsg.type:type_name -> perunremote.ChannelType
+	8,  // 28: perunremote.WatchUpdateMsg.request:type_name -> perunremote.WatchRequestMsg
+	1,  // 29: perunremote.WatchResponseMsg.code:type_name -> perunremote.ErrorCode
+	8,  // 30: perunremote.ForceCloseRequestMsg.latest:type_name -> perunremote.WatchRequestMsg
+	1,  // 31: perunremote.ForceCloseResponseMsg.code:type_name -> perunremote.ErrorCode
+	2,  // 32: perunremote.DisputeNotification.event:type_name -> perunremote.DisputeEvent
+	1,  // 33: perunremote.StopWatchResponseMsg.code:type_name -> perunremote.ErrorCode
+	1,  // 34: perunremote.DisputeStatusResponseMsg.code:type_name -> perunremote.ErrorCode
+	3,  // 35: perunremote.DisputeStatusResponseMsg.phase:type_name -> perunremote.DisputePhase
+	27, // 36: perunremote.ListWatchedResponseMsg.channels:type_name -> perunremote.WatchedChannel
+	37, // [37:37] is the sub-list for method output_type
+	37, // [37:37] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
 }
 
 func init() { file_perun_remote_proto_init() }
@@ -844,7 +2484,7 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchRequestMsg); i {
+			switch v := v.(*FundingProgressMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -856,7 +2496,7 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SignedWithdrawalAuth); i {
+			switch v := v.(*WatchRequestMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -868,7 +2508,7 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchResponseMsg); i {
+			switch v := v.(*WatchUpdateMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -880,7 +2520,7 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForceCloseRequestMsg); i {
+			switch v := v.(*SignedWithdrawalAuth); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -892,7 +2532,7 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForceCloseResponseMsg); i {
+			switch v := v.(*WatchResponseMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -904,6 +2544,30 @@ func file_perun_remote_proto_init() {
 			}
 		}
 		file_perun_remote_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForceCloseRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForceCloseResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DisputeNotification); i {
 			case 0:
 				return &v.state
@@ -915,6 +2579,162 @@ func file_perun_remote_proto_init() {
 				return nil
 			}
 		}
+		file_perun_remote_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopWatchRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopWatchResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HandshakeResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PongMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisputeStatusRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DisputeStatusResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListWatchedRequestMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListWatchedResponseMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_perun_remote_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchedChannel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_perun_remote_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*Message_FundingRequest)(nil),
@@ -924,19 +2744,34 @@ func file_perun_remote_proto_init() {
 		(*Message_ForceCloseRequest)(nil),
 		(*Message_ForceCloseResponse)(nil),
 		(*Message_DisputeNotification)(nil),
+		(*Message_HandshakeRequest)(nil),
+		(*Message_HandshakeResponse)(nil),
+		(*Message_StopWatchRequest)(nil),
+		(*Message_StopWatchResponse)(nil),
+		(*Message_FundingProgress)(nil),
+		(*Message_WatchUpdate)(nil),
+		(*Message_Ping)(nil),
+		(*Message_Pong)(nil),
+		(*Message_StatusRequest)(nil),
+		(*Message_StatusResponse)(nil),
+		(*Message_DisputeStatusRequest)(nil),
+		(*Message_DisputeStatusResponse)(nil),
+		(*Message_ListWatchedRequest)(nil),
+		(*Message_ListWatchedResponse)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_perun_remote_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      4,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_perun_remote_proto_goTypes,
 		DependencyIndexes: file_perun_remote_proto_depIdxs,
+		EnumInfos:         file_perun_remote_proto_enumTypes,
 		MessageInfos:      file_perun_remote_proto_msgTypes,
 	}.Build()
 	File_perun_remote_proto = out.File
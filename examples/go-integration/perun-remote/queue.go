@@ -0,0 +1,45 @@
+package remote
+
+import "sync"
+
+// keyedQueue runs tasks with the same key one after another in the order in
+// which they were submitted, while tasks with different keys run
+// concurrently.
+type keyedQueue struct {
+	mu      sync.Mutex
+	pending map[string][]func() // A key is present while its tasks run.
+}
+
+func newKeyedQueue() *keyedQueue {
+	return &keyedQueue{pending: make(map[string][]func())}
+}
+
+// Submit schedules the task after all previously submitted tasks with the
+// same key.
+func (q *keyedQueue) Submit(key string, task func()) {
+	q.mu.Lock()
+	tasks, running := q.pending[key]
+	q.pending[key] = append(tasks, task)
+	q.mu.Unlock()
+
+	if !running {
+		go q.run(key)
+	}
+}
+
+func (q *keyedQueue) run(key string) {
+	for {
+		q.mu.Lock()
+		tasks := q.pending[key]
+		if len(tasks) == 0 {
+			delete(q.pending, key)
+			q.mu.Unlock()
+			return
+		}
+		task := tasks[0]
+		q.pending[key] = tasks[1:]
+		q.mu.Unlock()
+
+		task()
+	}
+}
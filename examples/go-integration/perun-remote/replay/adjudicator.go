@@ -0,0 +1,192 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+
+	remote "go-integration/perun-remote"
+)
+
+// Withdrawal is a withdrawal recorded by the FakeAdjudicator.
+type Withdrawal struct {
+	Idx channel.Index
+	Tx  channel.Transaction
+	// Signatures on the withdrawal auths, one per asset.
+	AuthSigs []wallet.Sig
+}
+
+// FakeAdjudicator simulates the on-chain adjudicator in memory. Registered
+// states are immediately final (elapsed timeouts), so withdrawals can happen
+// right after a registration.
+type FakeAdjudicator struct {
+	mu         sync.Mutex
+	receiver   wallet.Address
+	registered map[channel.ID][]channel.Transaction
+	withdrawn  map[channel.ID][]Withdrawal
+	// Closed and replaced whenever a withdrawal is recorded.
+	withdrawnChanged chan struct{}
+	subs             map[channel.ID][]*fakeSub
+}
+
+var _ channel.Adjudicator = (*FakeAdjudicator)(nil)
+
+// NewFakeAdjudicator creates a FakeAdjudicator withdrawing to receiver.
+func NewFakeAdjudicator(receiver wallet.Address) *FakeAdjudicator {
+	return &FakeAdjudicator{
+		receiver:         receiver,
+		registered:       make(map[channel.ID][]channel.Transaction),
+		withdrawn:        make(map[channel.ID][]Withdrawal),
+		withdrawnChanged: make(chan struct{}),
+		subs:             make(map[channel.ID][]*fakeSub),
+	}
+}
+
+// Register registers the transaction on-chain if it is newer than the
+// currently registered one and notifies all subscribers.
+func (a *FakeAdjudicator) Register(_ context.Context, req channel.AdjudicatorReq, _ []channel.SignedState) error {
+	a.mu.Lock()
+	id := req.Tx.State.ID
+	if reg := a.registered[id]; len(reg) > 0 && reg[len(reg)-1].State.Version >= req.Tx.State.Version {
+		a.mu.Unlock()
+		return fmt.Errorf("registering version %d: version %d already registered",
+			req.Tx.State.Version, reg[len(reg)-1].State.Version)
+	}
+	a.registered[id] = append(a.registered[id], req.Tx)
+	subs := a.subscribers(id)
+	a.mu.Unlock()
+
+	evt := channel.NewRegisteredEvent(id, &channel.ElapsedTimeout{}, req.Tx.State.Version, req.Tx.State, req.Tx.Sigs)
+	for _, sub := range subs {
+		sub.push(evt)
+	}
+	return nil
+}
+
+// Withdraw collects the withdrawal auth signatures from the request's account
+// and records the withdrawal. Only the currently registered state can be
+// withdrawn.
+func (a *FakeAdjudicator) Withdraw(_ context.Context, req channel.AdjudicatorReq, _ channel.StateMap) error {
+	id := req.Tx.State.ID
+	a.mu.Lock()
+	reg := a.registered[id]
+	a.mu.Unlock()
+	if len(reg) == 0 {
+		return errors.New("withdrawing unregistered channel")
+	}
+	if v := reg[len(reg)-1].State.Version; v != req.Tx.State.Version {
+		return fmt.Errorf("withdrawing version %d, but version %d is registered", req.Tx.State.Version, v)
+	}
+
+	w := Withdrawal{Idx: req.Idx, Tx: req.Tx}
+	for i, bals := range req.Tx.State.Balances {
+		enc, err := remote.EncodeWithdrawalAuth(id, req.Params.Parts[req.Idx], a.receiver, bals[req.Idx])
+		if err != nil {
+			return fmt.Errorf("encoding withdrawal auth %d: %w", i, err)
+		}
+		sig, err := req.Acc.SignData(enc)
+		if err != nil {
+			return fmt.Errorf("signing withdrawal auth %d: %w", i, err)
+		}
+		w.AuthSigs = append(w.AuthSigs, sig)
+	}
+
+	a.mu.Lock()
+	a.withdrawn[id] = append(a.withdrawn[id], w)
+	close(a.withdrawnChanged)
+	a.withdrawnChanged = make(chan struct{})
+	subs := a.subscribers(id)
+	a.mu.Unlock()
+
+	evt := channel.NewConcludedEvent(id, &channel.ElapsedTimeout{}, req.Tx.State.Version)
+	for _, sub := range subs {
+		sub.push(evt)
+	}
+	return nil
+}
+
+// Progress is not supported by the FakeAdjudicator.
+func (a *FakeAdjudicator) Progress(context.Context, channel.ProgressReq) error {
+	return errors.New("progress not supported")
+}
+
+// Subscribe returns a subscription on the adjudicator events of channel id.
+func (a *FakeAdjudicator) Subscribe(_ context.Context, id channel.ID) (channel.AdjudicatorSubscription, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sub := &fakeSub{events: make(chan channel.AdjudicatorEvent, 16), closed: make(chan struct{})}
+	a.subs[id] = append(a.subs[id], sub)
+	return sub, nil
+}
+
+// Registered returns the versions registered on-chain for channel id, in
+// the order of registration.
+func (a *FakeAdjudicator) Registered(id channel.ID) []uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	versions := make([]uint64, len(a.registered[id]))
+	for i, tx := range a.registered[id] {
+		versions[i] = tx.State.Version
+	}
+	return versions
+}
+
+// AwaitWithdrawal blocks until channel id is withdrawn or ctx is done. It
+// returns the first withdrawal of the channel.
+func (a *FakeAdjudicator) AwaitWithdrawal(ctx context.Context, id channel.ID) (Withdrawal, error) {
+	for {
+		a.mu.Lock()
+		withdrawn, changed := a.withdrawn[id], a.withdrawnChanged
+		a.mu.Unlock()
+		if len(withdrawn) > 0 {
+			return withdrawn[0], nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return Withdrawal{}, ctx.Err()
+		}
+	}
+}
+
+// subscribers returns a copy of the subscriptions of channel id, so that
+// events can be pushed without holding a.mu. It must be called with a.mu held.
+func (a *FakeAdjudicator) subscribers(id channel.ID) []*fakeSub {
+	return append([]*fakeSub(nil), a.subs[id]...)
+}
+
+type fakeSub struct {
+	events    chan channel.AdjudicatorEvent
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *fakeSub) push(evt channel.AdjudicatorEvent) {
+	select {
+	case s.events <- evt:
+	case <-s.closed:
+	}
+}
+
+func (s *fakeSub) Next() channel.AdjudicatorEvent {
+	select {
+	case evt := <-s.events:
+		return evt
+	case <-s.closed:
+		return nil
+	}
+}
+
+func (s *fakeSub) Err() error { return nil }
+
+func (s *fakeSub) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return nil
+}
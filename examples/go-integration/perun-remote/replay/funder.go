@@ -0,0 +1,39 @@
+package replay
+
+import (
+	"context"
+	"sync"
+
+	"perun.network/go-perun/channel"
+)
+
+// FakeFunder records funding requests instead of depositing on-chain. Every
+// request succeeds unless Err is set.
+type FakeFunder struct {
+	mu     sync.Mutex
+	funded []channel.FundingReq
+	// Err is returned by Fund if set.
+	Err error
+}
+
+var _ channel.Funder = (*FakeFunder)(nil)
+
+// Fund records the request.
+func (f *FakeFunder) Fund(_ context.Context, req channel.FundingReq) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+	f.funded = append(f.funded, req)
+	return nil
+}
+
+// Funded returns the recorded funding requests, in the order of arrival.
+func (f *FakeFunder) Funded() []channel.FundingReq {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]channel.FundingReq(nil), f.funded...)
+}
@@ -0,0 +1,259 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	perunProto "perun.network/go-perun/wire/protobuf"
+
+	remote "go-integration/perun-remote"
+	"go-integration/perun-remote/codec"
+	"go-integration/perun-remote/proto"
+)
+
+// Harness serves a remote.Server over an in-process pipe, backed by the fake
+// adjudicator, watcher and funder. It plays the client side of the protocol,
+// so that crafted messages can be sent and the responses checked without a
+// blockchain or TCP connection.
+type Harness struct {
+	Adjudicator *FakeAdjudicator
+	Funder      *FakeFunder
+	Server      *remote.Server
+
+	conn     net.Conn
+	received chan *proto.Message
+	readErr  error // set before received is closed
+
+	mu      sync.Mutex
+	pending []*proto.Message // received, but not awaited yet
+}
+
+// NewHarness starts a server whose adjudicator withdraws to receiver and
+// connects to it. The handshake is not sent.
+func NewHarness(receiver wallet.Address, opts ...remote.ServerOption) (*Harness, error) {
+	adj := NewFakeAdjudicator(receiver)
+	funder := &FakeFunder{}
+	server, err := remote.NewConnServer(
+		remote.NewWatcherService(NewFakeWatcher(adj), adj),
+		remote.NewFunderService(funder),
+		opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client, conn := net.Pipe()
+	go server.ServeConn(conn)
+
+	h := &Harness{
+		Adjudicator: adj,
+		Funder:      funder,
+		Server:      server,
+		conn:        client,
+		received:    make(chan *proto.Message, 16),
+	}
+	go h.receive()
+	return h, nil
+}
+
+// Close closes the connection and the server.
+func (h *Harness) Close() error {
+	h.conn.Close()
+	return h.Server.Close()
+}
+
+// Send sends a message to the server.
+func (h *Harness) Send(msg *proto.Message) error {
+	return codec.Codec{}.WriteMessage(h.conn, msg)
+}
+
+// Await returns the first message from the server matching match. Messages
+// not matching are kept for later calls, so that asynchronous notifications
+// are not lost while waiting for a response.
+func (h *Harness) Await(ctx context.Context, match func(*proto.Message) bool) (*proto.Message, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, msg := range h.pending {
+		if match(msg) {
+			h.pending = append(h.pending[:i], h.pending[i+1:]...)
+			return msg, nil
+		}
+	}
+	for {
+		select {
+		case msg, ok := <-h.received:
+			if !ok {
+				return nil, fmt.Errorf("connection closed: %w", h.readErr)
+			}
+			if match(msg) {
+				return msg, nil
+			}
+			h.pending = append(h.pending, msg)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Received returns the messages received but not awaited yet, without
+// blocking.
+func (h *Harness) Received() []*proto.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for {
+		select {
+		case msg, ok := <-h.received:
+			if ok {
+				h.pending = append(h.pending, msg)
+				continue
+			}
+		default:
+		}
+		msgs := h.pending
+		h.pending = nil
+		return msgs
+	}
+}
+
+// Request sends the message and awaits the response matching match.
+func (h *Harness) Request(ctx context.Context, msg *proto.Message, match func(*proto.Message) bool) (*proto.Message, error) {
+	if err := h.Send(msg); err != nil {
+		return nil, err
+	}
+	return h.Await(ctx, match)
+}
+
+// Handshake performs the handshake with the server's protocol version.
+func (h *Harness) Handshake(ctx context.Context) error {
+	resp, err := h.Request(ctx, &proto.Message{Msg: &proto.Message_HandshakeRequest{
+		HandshakeRequest: &proto.HandshakeRequestMsg{Version: remote.ProtocolVersion}}},
+		func(m *proto.Message) bool { return m.GetHandshakeResponse() != nil })
+	if err != nil {
+		return err
+	}
+	if hs := resp.GetHandshakeResponse(); !hs.Success {
+		return fmt.Errorf("handshake rejected: %s", hs.Error)
+	}
+	return nil
+}
+
+func (h *Harness) receive() {
+	defer close(h.received)
+	for {
+		msg, err := codec.Codec{}.ReadMessage(h.conn)
+		if err != nil {
+			h.readErr = err
+			return
+		}
+		h.received <- msg
+	}
+}
+
+// RunProtocol replays the scenario through the remote protocol: the states
+// are sent as a watch request followed by watch updates, the counterparty
+// registers the outdated state and the service has to report the dispute and
+// withdraw the newest state. The withdrawal auths of the newest state are sent
+// with every state, as only the newest one is withdrawn.
+func (s Scenario) RunProtocol(ctx context.Context, opts ...remote.ServerOption) (*Result, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	newest := s.newest()
+	id := newest.State.ID
+
+	h, err := NewHarness(s.Receiver, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+	if err := h.Handshake(ctx); err != nil {
+		return nil, err
+	}
+
+	for i, signed := range s.States {
+		req, err := s.watchRequest(signed)
+		if err != nil {
+			return nil, err
+		}
+		msg := &proto.Message{Msg: &proto.Message_WatchRequest{WatchRequest: req}}
+		if i > 0 {
+			msg = &proto.Message{Msg: &proto.Message_WatchUpdate{WatchUpdate: &proto.WatchUpdateMsg{
+				Request:     req,
+				BaseVersion: s.States[i-1].State.Version}}}
+		}
+		version := signed.State.Version
+		resp, err := h.Request(ctx, msg, func(m *proto.Message) bool {
+			return m.GetWatchResponse() != nil && m.GetWatchResponse().Version == version
+		})
+		if err != nil {
+			return nil, fmt.Errorf("watching version %d: %w", version, err)
+		}
+		if wr := resp.GetWatchResponse(); !wr.Success {
+			return nil, fmt.Errorf("watching version %d: %s (%v)", version, wr.Error, wr.Code)
+		}
+	}
+
+	// Any other participant registers the outdated state.
+	old := s.States[s.Registered]
+	err = h.Adjudicator.Register(ctx, channel.AdjudicatorReq{
+		Params: old.Params,
+		Tx:     channel.Transaction{State: old.State, Sigs: old.Sigs},
+		Idx:    (s.Participant + 1) % channel.Index(len(old.Params.Parts)),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("registering outdated state: %w", err)
+	}
+
+	w, err := h.Adjudicator.AwaitWithdrawal(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("awaiting withdrawal: %w", err)
+	}
+
+	// At least the outdated registration is notified before the withdrawal.
+	res := &Result{Registered: h.Adjudicator.Registered(id), Withdrawal: w}
+	isNotification := func(m *proto.Message) bool { return m.GetDisputeNotification() != nil }
+	msg, err := h.Await(ctx, isNotification)
+	if err != nil {
+		return nil, fmt.Errorf("awaiting dispute notification: %w", err)
+	}
+	res.Notified = append(res.Notified, msg.GetDisputeNotification().Version)
+	for _, msg := range h.Received() {
+		if isNotification(msg) {
+			res.Notified = append(res.Notified, msg.GetDisputeNotification().Version)
+		}
+	}
+	return res, nil
+}
+
+// watchRequest encodes a watch request of the scenario's participant for the
+// signed state.
+func (s Scenario) watchRequest(signed channel.SignedState) (*proto.WatchRequestMsg, error) {
+	state, err := perunProto.FromSignedState(&signed)
+	if err != nil {
+		return nil, fmt.Errorf("encoding state: %w", err)
+	}
+	if s.Receiver == nil {
+		return nil, errors.New("scenario has no receiver")
+	}
+	receiver, err := s.Receiver.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding receiver: %w", err)
+	}
+	req := &proto.WatchRequestMsg{
+		Participant: uint32(s.Participant),
+		State:       state,
+	}
+	for _, sig := range s.WithdrawalAuths {
+		req.WithdrawalAuths = append(req.WithdrawalAuths, &proto.SignedWithdrawalAuth{
+			Sig:      sig,
+			Receiver: receiver,
+		})
+	}
+	return req, nil
+}
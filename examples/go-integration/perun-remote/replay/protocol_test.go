@@ -0,0 +1,99 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	"perun.network/go-perun/channel"
+
+	remote "go-integration/perun-remote"
+	"go-integration/perun-remote/proto"
+)
+
+func TestRunProtocol(t *testing.T) {
+	tests := []struct {
+		name        string
+		numParts    int
+		participant channel.Index
+	}{
+		{name: "first of two", numParts: 2, participant: 0},
+		{name: "second of two", numParts: 2, participant: 1},
+		{name: "last of three", numParts: 3, participant: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := recordScenario(t, tt.numParts, tt.participant)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			res, err := s.RunProtocol(ctx)
+			if err != nil {
+				t.Fatalf("running scenario: %v", err)
+			}
+			if err := s.Check(res); err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Registered) != 2 || res.Registered[0] != 1 || res.Registered[1] != 3 {
+				t.Errorf("registered versions %v, want [1 3]", res.Registered)
+			}
+			if len(res.Notified) == 0 || res.Notified[0] != 1 {
+				t.Errorf("notified versions %v, want the outdated version 1 first", res.Notified)
+			}
+		})
+	}
+}
+
+func TestHarnessHandshake(t *testing.T) {
+	receiver := ethwallet.AsWalletAddr(common.Address{0xff})
+	isHandshake := func(m *proto.Message) bool { return m.GetHandshakeResponse() != nil }
+
+	t.Run("compatible version", func(t *testing.T) {
+		h, err := NewHarness(receiver)
+		if err != nil {
+			t.Fatalf("creating harness: %v", err)
+		}
+		defer h.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := h.Handshake(ctx); err != nil {
+			t.Fatalf("handshake: %v", err)
+		}
+		// Requests are handled after the handshake.
+		resp, err := h.Request(ctx, &proto.Message{Msg: &proto.Message_StatusRequest{
+			StatusRequest: &proto.StatusRequestMsg{}}},
+			func(m *proto.Message) bool { return m.GetStatusResponse() != nil })
+		if err != nil {
+			t.Fatalf("requesting status: %v", err)
+		}
+		if v := resp.GetStatusResponse().ProtocolVersion; v != remote.ProtocolVersion {
+			t.Errorf("status reports protocol version %d, want %d", v, remote.ProtocolVersion)
+		}
+	})
+
+	t.Run("incompatible version", func(t *testing.T) {
+		h, err := NewHarness(receiver)
+		if err != nil {
+			t.Fatalf("creating harness: %v", err)
+		}
+		defer h.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		resp, err := h.Request(ctx, &proto.Message{Msg: &proto.Message_HandshakeRequest{
+			HandshakeRequest: &proto.HandshakeRequestMsg{Version: remote.ProtocolVersion + 1}}}, isHandshake)
+		if err != nil {
+			t.Fatalf("sending handshake: %v", err)
+		}
+		if hs := resp.GetHandshakeResponse(); hs.Success || hs.Version != remote.ProtocolVersion {
+			t.Errorf("got handshake response %v, want a rejection with version %d", hs, remote.ProtocolVersion)
+		}
+		// The server closes the connection after rejecting the handshake.
+		if _, err := h.Await(ctx, func(*proto.Message) bool { return true }); err == nil {
+			t.Error("connection still open after a rejected handshake")
+		}
+	})
+}
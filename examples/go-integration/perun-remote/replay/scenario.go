@@ -0,0 +1,193 @@
+// Package replay replays recorded dispute scenarios against the
+// WatcherService using an in-memory adjudicator and watcher, so that the
+// dispute logic can be regression-tested without a blockchain.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+
+	remote "go-integration/perun-remote"
+)
+
+// Scenario is a recorded dispute: the watched participant knows a sequence of
+// signed states, and the counterparty registers an outdated one on-chain.
+type Scenario struct {
+	Name        string
+	Participant channel.Index
+	// Signed states of the channel, in increasing version order.
+	States []channel.SignedState
+	// Signatures of Participant on the withdrawal auths of the newest state,
+	// one per asset.
+	WithdrawalAuths []wallet.Sig
+	Receiver        wallet.Address
+	// Index into States of the outdated state registered by the counterparty.
+	Registered int
+}
+
+// Result is the outcome of replaying a Scenario.
+type Result struct {
+	// Versions registered on-chain, in the order of registration.
+	Registered []uint64
+	// Versions reported to the client as registered or progressed disputes.
+	Notified   []uint64
+	Withdrawal Withdrawal
+}
+
+// Record signs the given states with all participant accounts and the
+// withdrawal auths of the newest state with the account of participant idx.
+func Record(
+	name string,
+	params *channel.Params,
+	states []*channel.State,
+	accs []wallet.Account,
+	idx channel.Index,
+	receiver wallet.Address,
+	registered int,
+) (Scenario, error) {
+	if len(accs) != len(params.Parts) {
+		return Scenario{}, errors.New("need one account per participant")
+	}
+	s := Scenario{
+		Name:        name,
+		Participant: idx,
+		Receiver:    receiver,
+		Registered:  registered,
+	}
+	for _, state := range states {
+		signed := channel.SignedState{Params: params, State: state}
+		for _, acc := range accs {
+			sig, err := channel.Sign(acc, state)
+			if err != nil {
+				return Scenario{}, fmt.Errorf("signing version %d: %w", state.Version, err)
+			}
+			signed.Sigs = append(signed.Sigs, sig)
+		}
+		s.States = append(s.States, signed)
+	}
+
+	newest := states[len(states)-1]
+	for i, bals := range newest.Balances {
+		enc, err := remote.EncodeWithdrawalAuth(newest.ID, params.Parts[idx], receiver, bals[idx])
+		if err != nil {
+			return Scenario{}, fmt.Errorf("encoding withdrawal auth %d: %w", i, err)
+		}
+		sig, err := accs[idx].SignData(enc)
+		if err != nil {
+			return Scenario{}, fmt.Errorf("signing withdrawal auth %d: %w", i, err)
+		}
+		s.WithdrawalAuths = append(s.WithdrawalAuths, sig)
+	}
+	return s, s.validate()
+}
+
+func (s Scenario) validate() error {
+	if len(s.States) == 0 {
+		return errors.New("no states recorded")
+	}
+	if s.Registered < 0 || s.Registered >= len(s.States)-1 {
+		return errors.New("registered state must be an outdated one")
+	}
+	for i := 1; i < len(s.States); i++ {
+		if s.States[i].State.Version <= s.States[i-1].State.Version {
+			return errors.New("states not in increasing version order")
+		}
+	}
+	return nil
+}
+
+func (s Scenario) newest() channel.SignedState {
+	return s.States[len(s.States)-1]
+}
+
+// Run feeds all recorded states to a WatcherService, lets the counterparty
+// register the outdated state and waits until the service withdrew.
+func (s Scenario) Run(ctx context.Context) (*Result, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	newest := s.newest()
+	params := newest.Params
+	id := newest.State.ID
+
+	adj := NewFakeAdjudicator(s.Receiver)
+	service := remote.NewWatcherService(NewFakeWatcher(adj), adj)
+
+	signer := remote.NewPreSignedAccount(params.Parts[s.Participant])
+	for i, sig := range s.WithdrawalAuths {
+		enc, err := remote.EncodeWithdrawalAuth(id, signer.Address(), s.Receiver, newest.State.Balances[i][s.Participant])
+		if err != nil {
+			return nil, fmt.Errorf("encoding withdrawal auth %d: %w", i, err)
+		}
+		signer.AddSig(enc, sig)
+	}
+
+	notified := make(chan uint64, len(s.States)+1)
+	onDispute := func(evt channel.AdjudicatorEvent) {
+		select {
+		case notified <- evt.Version():
+		default:
+		}
+	}
+	for _, signed := range s.States {
+		err := service.Watch(remote.WatchRequestMsg{
+			Participant: s.Participant,
+			State:       signed,
+			AuthSigner:  signer,
+		}, onDispute)
+		if err != nil {
+			return nil, fmt.Errorf("watching version %d: %w", signed.State.Version, err)
+		}
+	}
+
+	// Any other participant registers the outdated state.
+	old := s.States[s.Registered]
+	err := adj.Register(ctx, channel.AdjudicatorReq{
+		Params: params,
+		Tx:     channel.Transaction{State: old.State, Sigs: old.Sigs},
+		Idx:    (s.Participant + 1) % channel.Index(len(params.Parts)),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("registering outdated state: %w", err)
+	}
+
+	w, err := adj.AwaitWithdrawal(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("awaiting withdrawal: %w", err)
+	}
+
+	res := &Result{Registered: adj.Registered(id), Withdrawal: w}
+	for len(notified) > 0 {
+		res.Notified = append(res.Notified, <-notified)
+	}
+	return res, nil
+}
+
+// Check verifies that the newest state was withdrawn with the recorded
+// withdrawal auths.
+func (s Scenario) Check(res *Result) error {
+	newest := s.newest()
+	if res.Withdrawal.Tx.State.Version != newest.State.Version {
+		return fmt.Errorf("withdrew version %d, expected newest version %d",
+			res.Withdrawal.Tx.State.Version, newest.State.Version)
+	}
+	if res.Withdrawal.Idx != s.Participant {
+		return fmt.Errorf("withdrew for participant %d, expected %d", res.Withdrawal.Idx, s.Participant)
+	}
+	if err := newest.State.Allocation.Equal(&res.Withdrawal.Tx.State.Allocation); err != nil {
+		return fmt.Errorf("withdrawn allocation differs from newest state: %w", err)
+	}
+	if len(res.Withdrawal.AuthSigs) != len(s.WithdrawalAuths) {
+		return fmt.Errorf("withdrew %d assets, expected %d", len(res.Withdrawal.AuthSigs), len(s.WithdrawalAuths))
+	}
+	for i, sig := range s.WithdrawalAuths {
+		if string(res.Withdrawal.AuthSigs[i]) != string(sig) {
+			return fmt.Errorf("withdrawal auth %d does not match the recorded one", i)
+		}
+	}
+	return nil
+}
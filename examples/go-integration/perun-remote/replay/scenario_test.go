@@ -0,0 +1,90 @@
+package replay
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	ethwtest "github.com/perun-network/perun-eth-backend/wallet/test"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+)
+
+// recordScenario records a dispute of a channel with numParts participants
+// over three states, in which the first state is registered.
+func recordScenario(t *testing.T, numParts int, participant channel.Index) Scenario {
+	t.Helper()
+	rng := rand.New(rand.NewSource(1))
+	w := ethwtest.NewTmpWallet()
+	accs := make([]wallet.Account, numParts)
+	parts := make([]wallet.Address, numParts)
+	for i := range accs {
+		accs[i] = w.NewRandomAccount(rng)
+		parts[i] = accs[i].Address()
+	}
+	params := channel.NewParamsUnsafe(60, parts, channel.NoApp(), big.NewInt(1), true, false)
+	asset := ethchannel.NewAsset(big.NewInt(1337), common.Address{0x10})
+
+	var states []*channel.State
+	for version := uint64(1); version <= 3; version++ {
+		alloc := channel.NewAllocation(numParts, asset)
+		for i := 0; i < numParts; i++ {
+			// The participant gains with every version.
+			bal := int64(100)
+			if channel.Index(i) == participant {
+				bal += int64(version) * 10
+			}
+			alloc.SetBalance(channel.Index(i), asset, big.NewInt(bal))
+		}
+		states = append(states, &channel.State{
+			ID:         params.ID(),
+			Version:    version,
+			App:        channel.NoApp(),
+			Allocation: *alloc,
+			Data:       channel.NoData(),
+		})
+	}
+
+	receiver := ethwallet.AsWalletAddr(common.Address{0xff})
+	s, err := Record("outdated state registered", params, states, accs, participant, receiver, 0)
+	if err != nil {
+		t.Fatalf("recording scenario: %v", err)
+	}
+	return s
+}
+
+func TestScenario(t *testing.T) {
+	tests := []struct {
+		name        string
+		numParts    int
+		participant channel.Index
+	}{
+		{name: "first of two", numParts: 2, participant: 0},
+		{name: "second of two", numParts: 2, participant: 1},
+		{name: "last of three", numParts: 3, participant: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := recordScenario(t, tt.numParts, tt.participant)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			res, err := s.Run(ctx)
+			if err != nil {
+				t.Fatalf("running scenario: %v", err)
+			}
+			if err := s.Check(res); err != nil {
+				t.Fatal(err)
+			}
+			// The outdated state is refuted with the newest one.
+			if len(res.Registered) != 2 || res.Registered[0] != 1 || res.Registered[1] != 3 {
+				t.Errorf("registered versions %v, want [1 3]", res.Registered)
+			}
+		})
+	}
+}
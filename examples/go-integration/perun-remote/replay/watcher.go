@@ -0,0 +1,138 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/watcher"
+)
+
+// FakeWatcher is a minimal watcher.Watcher. Like the local watcher of
+// go-perun, it refutes registrations of outdated states by registering the
+// latest published state.
+type FakeWatcher struct {
+	mu       sync.Mutex
+	adj      channel.Adjudicator
+	watching map[channel.ID]*fakeWatchedChannel
+}
+
+var _ watcher.Watcher = (*FakeWatcher)(nil)
+
+// NewFakeWatcher creates a FakeWatcher refuting via adj.
+func NewFakeWatcher(adj channel.Adjudicator) *FakeWatcher {
+	return &FakeWatcher{
+		adj:      adj,
+		watching: make(map[channel.ID]*fakeWatchedChannel),
+	}
+}
+
+type fakeWatchedChannel struct {
+	mu     sync.Mutex
+	params *channel.Params
+	latest channel.Transaction
+	sub    channel.AdjudicatorSubscription
+	events chan channel.AdjudicatorEvent
+}
+
+func (w *FakeWatcher) StartWatchingLedgerChannel(
+	ctx context.Context,
+	signed channel.SignedState,
+) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := signed.State.ID
+	if _, ok := w.watching[id]; ok {
+		return nil, nil, errors.New("already watching channel")
+	}
+	sub, err := w.adj.Subscribe(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch := &fakeWatchedChannel{
+		params: signed.Params,
+		latest: channel.Transaction{State: signed.State, Sigs: signed.Sigs},
+		sub:    sub,
+		events: make(chan channel.AdjudicatorEvent, 16),
+	}
+	w.watching[id] = ch
+	go ch.run(w.adj)
+	return ch, ch, nil
+}
+
+func (w *FakeWatcher) StartWatchingSubChannel(
+	context.Context,
+	channel.ID,
+	channel.SignedState,
+) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	return nil, nil, errors.New("sub-channels not supported")
+}
+
+func (w *FakeWatcher) StopWatching(_ context.Context, id channel.ID) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, ok := w.watching[id]
+	if !ok {
+		return errors.New("channel not watched")
+	}
+	delete(w.watching, id)
+	return ch.sub.Close()
+}
+
+// Publish implements watcher.StatesPub.
+func (ch *fakeWatchedChannel) Publish(_ context.Context, tx channel.Transaction) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if tx.State.Version > ch.latest.State.Version {
+		ch.latest = tx
+	}
+	return nil
+}
+
+// EventStream implements watcher.AdjudicatorSub.
+func (ch *fakeWatchedChannel) EventStream() <-chan channel.AdjudicatorEvent {
+	return ch.events
+}
+
+// Err implements watcher.AdjudicatorSub. It returns the error of the
+// adjudicator subscription once the event stream is closed.
+func (ch *fakeWatchedChannel) Err() error {
+	return ch.sub.Err()
+}
+
+func (ch *fakeWatchedChannel) run(adj channel.Adjudicator) {
+	defer close(ch.events)
+	for {
+		evt := ch.sub.Next()
+		if evt == nil {
+			return
+		}
+
+		if reg, ok := evt.(*channel.RegisteredEvent); ok {
+			ch.mu.Lock()
+			latest := ch.latest
+			ch.mu.Unlock()
+			if reg.Version() < latest.State.Version {
+				err := adj.Register(context.Background(), channel.AdjudicatorReq{
+					Params: ch.params,
+					Tx:     latest,
+				}, nil)
+				if err != nil {
+					log.Errorf("FakeWatcher: refuting: %v", err)
+				}
+			}
+		}
+
+		select {
+		case ch.events <- evt:
+		default:
+			log.Warnf("FakeWatcher: dropping event %T, event stream full", evt)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package remote
 
 import (
-	"encoding/binary"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
-
-	protobuf "google.golang.org/protobuf/proto"
+	"os"
+	stdsync "sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -14,9 +16,34 @@ import (
 
 	"perun.network/go-perun/channel"
 
+	"go-integration/perun-remote/codec"
 	"go-integration/perun-remote/proto"
 )
 
+// ProtocolVersion is the version of the remote protocol spoken by the server.
+// Clients announce it in the handshake, which is the first message on a
+// connection. Connections that do not start with a handshake speak version 1.
+const ProtocolVersion = 1
+
+const (
+	// DefaultMaxFrameSize is the default limit for the size of received
+	// messages.
+	DefaultMaxFrameSize = codec.DefaultMaxFrameSize
+	// DefaultMaxConcurrentMessages is the default number of messages of a
+	// connection which are handled concurrently.
+	DefaultMaxConcurrentMessages = 16
+	// DefaultDrainTimeout is the default time Close waits for requests in
+	// flight.
+	DefaultDrainTimeout = 30 * time.Second
+	// DefaultIdleTimeout is the default time a connection may stay without
+	// receiving a message. It is disabled, as clients waiting for dispute
+	// notifications do not need to send anything.
+	DefaultIdleTimeout = 0
+	// DefaultPongTimeout is the default time the keepalive waits for the
+	// pong to a ping.
+	DefaultPongTimeout = 10 * time.Second
+)
+
 type Server struct {
 	sync.Closer
 
@@ -24,31 +51,139 @@ type Server struct {
 
 	watcher *WatcherService
 	funder  *FunderService
+
+	codec                 codec.Codec
+	maxConcurrentMessages int
+	drainTimeout          time.Duration
+	idleTimeout           time.Duration
+	pingInterval          time.Duration // zero if the server does not ping
+	pongTimeout           time.Duration
+	limits                Limits
+
+	// Tracks the requests in flight. Once draining is set, no new requests
+	// are accepted.
+	drainMu  stdsync.Mutex
+	draining bool
+	handlers stdsync.WaitGroup
+
+	started time.Time
+}
+
+// ServerOption configures optional behavior of a Server.
+type ServerOption func(*Server)
+
+// WithMaxFrameSize sets the maximum size of received messages. Connections
+// announcing larger messages are closed.
+func WithMaxFrameSize(size uint32) ServerOption {
+	return func(s *Server) {
+		s.codec.MaxFrameSize = size
+	}
+}
+
+// WithMaxConcurrentMessages sets the number of messages of a single connection
+// which are handled concurrently. Further messages wait for a handler to
+// finish, while the connection is still read, e.g. for keepalives.
+func WithMaxConcurrentMessages(n int) ServerOption {
+	return func(s *Server) {
+		s.maxConcurrentMessages = n
+	}
+}
+
+// WithDrainTimeout sets how long Close waits for requests in flight before
+// closing the connections.
+func WithDrainTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.drainTimeout = timeout
+	}
+}
+
+// WithIdleTimeout sets how long a connection may stay without receiving a
+// message, including the time to receive a started message. Idle connections
+// are closed. A zero timeout disables the deadline.
+func WithIdleTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.idleTimeout = timeout
+	}
+}
+
+// WithLimits sets the limits of the channels in requests. Requests exceeding
+// them are rejected before they are decoded.
+func WithLimits(limits Limits) ServerOption {
+	return func(s *Server) {
+		s.limits = limits
+	}
 }
 
+// WithKeepalive makes the server ping each connection every interval and close
+// it if the pong does not arrive within the timeout. This keeps connections
+// through NATs alive and detects dead clients. A zero interval disables it.
+func WithKeepalive(interval, timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.pingInterval = interval
+		s.pongTimeout = timeout
+	}
+}
+
+// NewServer creates a server listening for clients on addr. Connections are
+// accepted by Serve.
 func NewServer(
 	watcher *WatcherService,
 	funder *FunderService,
-	port uint16,
+	addr string,
+	opts ...ServerOption,
 ) (*Server, error) {
-	server, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	s, err := NewConnServer(watcher, funder, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := net.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("listener: %w", err)
 	}
+	s.server = server
 
-	s := &Server{
-		server: server,
+	s.OnCloseAlways(func() { server.Close() })
 
+	return s, nil
+}
+
+// NewConnServer creates a server without a listener. It only serves the
+// connections passed to ServeConn, e.g. in-process pipes in tests.
+func NewConnServer(
+	watcher *WatcherService,
+	funder *FunderService,
+	opts ...ServerOption,
+) (*Server, error) {
+	s := &Server{
 		watcher: watcher,
 		funder:  funder,
-	}
-
-	s.OnCloseAlways(func() { server.Close() })
 
+		codec:                 codec.Codec{MaxFrameSize: DefaultMaxFrameSize},
+		maxConcurrentMessages: DefaultMaxConcurrentMessages,
+		drainTimeout:          DefaultDrainTimeout,
+		idleTimeout:           DefaultIdleTimeout,
+		limits:                DefaultLimits,
+		started:               time.Now(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxConcurrentMessages < 1 {
+		return nil, fmt.Errorf("invalid concurrency limit: %d", s.maxConcurrentMessages)
+	}
+	if s.pingInterval > 0 && s.pongTimeout <= 0 {
+		return nil, fmt.Errorf("invalid pong timeout: %v", s.pongTimeout)
+	}
 	return s, nil
 }
 
+// Serve accepts connections until the server is closed. It returns right
+// away for servers created by NewConnServer.
 func (s *Server) Serve() {
+	if s.server == nil {
+		return
+	}
 	for {
 		conn, err := s.server.Accept()
 		if err != nil {
@@ -59,10 +194,115 @@ func (s *Server) Serve() {
 	}
 }
 
+// ServeConn serves a single client connection until it is closed by either
+// side. It blocks until the responses to all requests of the connection were
+// sent.
+func (s *Server) ServeConn(conn io.ReadWriteCloser) {
+	s.handleConn(conn)
+}
+
+// Close stops accepting new connections and requests, waits up to the drain
+// timeout for the requests in flight to be handled and then closes all
+// connections. An error is returned if the timeout expired.
+func (s *Server) Close() error {
+	s.drainMu.Lock()
+	s.draining = true
+	s.drainMu.Unlock()
+	if s.server != nil {
+		s.server.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(s.drainTimeout):
+		err = errors.New("timed out waiting for requests in flight")
+	}
+	if cerr := s.Closer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// keepalive pings the client every ping interval until done is closed. If a
+// pong does not arrive in time, the connection is closed.
+func (s *Server) keepalive(m *sync.Mutex, conn io.ReadWriteCloser, pongs <-chan uint64, done <-chan struct{}) {
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+
+	var nonce uint64
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		nonce++
+		if err := sendMsg(m, conn, &proto.Message{Msg: &proto.Message_Ping{
+			Ping: &proto.PingMsg{Nonce: nonce}}}); err != nil {
+			log.Errorf("sending ping: %v", err)
+			return
+		}
+
+		timeout := time.NewTimer(s.pongTimeout)
+	await:
+		for {
+			select {
+			case n := <-pongs:
+				if n == nonce {
+					break await
+				}
+			case <-timeout.C:
+				log.Warnf("Server: no pong within %v, closing connection", s.pongTimeout)
+				conn.Close()
+				return
+			case <-done:
+				timeout.Stop()
+				return
+			}
+		}
+		timeout.Stop()
+	}
+}
+
+// resetReadDeadline sets the read deadline of the connection to the idle
+// timeout from now, if the timeout is enabled and conn supports deadlines.
+func (s *Server) resetReadDeadline(conn io.ReadWriteCloser) error {
+	dconn, ok := conn.(interface{ SetReadDeadline(time.Time) error })
+	if s.idleTimeout <= 0 || !ok {
+		return nil
+	}
+	return dconn.SetReadDeadline(time.Now().Add(s.idleTimeout))
+}
+
+// startHandler registers a request in flight. It returns false if the server
+// is shutting down.
+func (s *Server) startHandler() bool {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+
+	if s.draining {
+		return false
+	}
+	s.handlers.Add(1)
+	return true
+}
+
 func (s *Server) handleConn(conn io.ReadWriteCloser) {
 	defer conn.Close()
 	s.OnCloseAlways(func() { conn.Close() })
 
+	// Responses of the handlers are sent before the connection is closed.
+	var connHandlers stdsync.WaitGroup
+	defer connHandlers.Wait()
+
 	var m sync.Mutex
 
 	// Called from another goroutine.
@@ -71,107 +311,392 @@ func (s *Server) handleConn(conn io.ReadWriteCloser) {
 	// Conn has to be capable of sending things from one goroutine while in a
 	// blocking receive call reading from it. Thanks to the mutex it is not
 	// possible to have multiple goroutines writing to conn.
-	send_dispute_notification := func(re *channel.RegisteredEvent) {
-		channelId := re.ID()
+	send_dispute_notification := func(evt channel.AdjudicatorEvent) {
+		channelId := evt.ID()
+		event := proto.DisputeEvent_REGISTERED
+		if _, ok := evt.(*channel.ProgressedEvent); ok {
+			event = proto.DisputeEvent_PROGRESSED
+		}
 		sendMsg(&m, conn, &proto.Message{
 			Msg: &proto.Message_DisputeNotification{
 				DisputeNotification: &proto.DisputeNotification{
 					ChannelId: channelId[:],
+					Version:   evt.Version(),
+					Event:     event,
 				},
 			},
 		})
 	}
 
+	if err := s.resetReadDeadline(conn); err != nil {
+		log.Errorf("setting read deadline: %v", err)
+		return
+	}
+	pending, err := s.handshake(&m, conn)
+	if errors.Is(err, errStatusOnly) {
+		return
+	} else if err != nil {
+		log.Errorf("handshake failed: %v", err)
+		return
+	}
+
+	// Receives the nonces of the pongs of the client.
+	pongs := make(chan uint64, 1)
+	if s.pingInterval > 0 {
+		done := make(chan struct{})
+		defer close(done)
+		go s.keepalive(&m, conn, pongs, done)
+	}
+
+	// Limits the number of concurrently handled messages.
+	sem := make(chan struct{}, s.maxConcurrentMessages)
+	// Messages concerning the same channel are handled in arrival order.
+	queue := newKeyedQueue()
+
 	for {
-		msg, err := recvMsg(conn)
-		if err != nil {
-			log.Errorf("decoding message failed: %v", err)
+		msg := pending
+		pending = nil
+		if msg == nil {
+			msg, err = s.readMessage(conn)
+			if err != nil {
+				return
+			}
+		}
+
+		// Keepalives are answered right away, bypassing the request handling.
+		switch msg := msg.GetMsg().(type) {
+		case *proto.Message_Ping:
+			if err := sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_Pong{
+				Pong: &proto.PongMsg{Nonce: msg.Ping.Nonce}}}); err != nil {
+				log.Errorf("sending pong: %v", err)
+				return
+			}
+			continue
+		case *proto.Message_Pong:
+			select {
+			case pongs <- msg.Pong.Nonce:
+			default: // Unsolicited pong, nobody is waiting.
+			}
+			continue
+		case *proto.Message_StatusRequest:
+			if err := s.sendStatus(&m, conn); err != nil {
+				log.Errorf("sending status: %v", err)
+				return
+			}
+			continue
+		case *proto.Message_ListWatchedRequest:
+			if err := s.sendWatched(&m, conn); err != nil {
+				log.Errorf("sending watched channels: %v", err)
+				return
+			}
+			continue
+		}
+
+		if !s.startHandler() {
+			log.Warn("Server: Dropping request, shutting down")
 			return
 		}
+		connHandlers.Add(1)
+		handle := func() {
+			// The slot is taken by the handler rather than the read loop, so
+			// slow handlers do not stop keepalives from being read.
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+				connHandlers.Done()
+				s.handlers.Done()
+			}()
 
-		go func() {
 			switch msg := msg.GetMsg().(type) {
 			case *proto.Message_WatchRequest:
 				log.Warn("Server: Got watch request / update notification")
-				req, err := ParseWatchRequestMsg(msg.WatchRequest)
+				req, err := s.limits.ParseWatchRequestMsg(msg.WatchRequest)
 				if err != nil {
 					log.Errorf("Invalid watch message: %v", err)
+					errStr, code := errorResponse(fmt.Errorf("%w: %v", ErrInvalidRequest, err), proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_WatchResponse{
+						WatchResponse: &proto.WatchResponseMsg{Error: errStr, Code: code}}})
 					return
 				}
 				if err = s.watcher.Watch(*req, send_dispute_notification); err != nil {
 					log.Errorf("Watching channel failed: %v", err)
 				}
+				errStr, code := errorResponse(err, proto.ErrorCode_INTERNAL_ERROR)
+				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_WatchResponse{
+					WatchResponse: &proto.WatchResponseMsg{
+						ChannelId: req.State.State.ID[:],
+						Version:   req.State.State.Version,
+						Success:   err == nil,
+						Error:     errStr,
+						Code:      code}}})
+			case *proto.Message_WatchUpdate:
+				log.Warn("Server: Got watch update")
+				req, err := s.limits.ParseWatchUpdateMsg(msg.WatchUpdate)
+				if err != nil {
+					log.Errorf("Invalid watch update: %v", err)
+					if !errors.Is(err, ErrOutdatedVersion) {
+						err = fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+					}
+					errStr, code := errorResponse(err, proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_WatchResponse{
+						WatchResponse: &proto.WatchResponseMsg{Error: errStr, Code: code}}})
+					return
+				}
+				if err = s.watcher.Update(*req); err != nil {
+					log.Errorf("Updating watched channel failed: %v", err)
+				}
+				errStr, code := errorResponse(err, proto.ErrorCode_INTERNAL_ERROR)
 				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_WatchResponse{
 					WatchResponse: &proto.WatchResponseMsg{
 						ChannelId: req.State.State.ID[:],
 						Version:   req.State.State.Version,
-						Success:   err == nil}}})
+						Success:   err == nil,
+						Error:     errStr,
+						Code:      code}}})
 			case *proto.Message_ForceCloseRequest:
 				log.Warn("Server: Got dispute request")
-				req, err := ParseForceCloseRequestMsg(msg.ForceCloseRequest)
+				req, err := s.limits.ParseForceCloseRequestMsg(msg.ForceCloseRequest)
 				if err != nil {
 					log.Errorf("Invalid force-close message: %v", err)
+					errStr, code := errorResponse(fmt.Errorf("%w: %v", ErrInvalidRequest, err), proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_ForceCloseResponse{
+						ForceCloseResponse: &proto.ForceCloseResponseMsg{Error: errStr, Code: code}}})
 					return
 				}
-				if err := s.watcher.StartDispute(*req); err != nil {
+				if err = s.watcher.StartDispute(*req); err != nil {
 					log.Errorf("Disputing failed: %v", err)
 				}
+				errStr, code := errorResponse(err, proto.ErrorCode_DISPUTE_FAILED)
 				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_ForceCloseResponse{
 					ForceCloseResponse: &proto.ForceCloseResponseMsg{
 						ChannelId: req.ChannelId[:],
-						Success:   err == nil}}})
+						Success:   err == nil,
+						Error:     errStr,
+						Code:      code}}})
+			case *proto.Message_StopWatchRequest:
+				log.Warn("Server: Got stop watch request")
+				var id channel.ID
+				if len(msg.StopWatchRequest.ChannelId) != len(id) {
+					errStr, code := errorResponse(fmt.Errorf("%w: invalid channel ID", ErrInvalidRequest), proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_StopWatchResponse{
+						StopWatchResponse: &proto.StopWatchResponseMsg{Error: errStr, Code: code}}})
+					return
+				}
+				copy(id[:], msg.StopWatchRequest.ChannelId)
+				err := s.watcher.StopWatch(id)
+				if err != nil {
+					log.Errorf("Stopping watch failed: %v", err)
+				}
+				errStr, code := errorResponse(err, proto.ErrorCode_INTERNAL_ERROR)
+				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_StopWatchResponse{
+					StopWatchResponse: &proto.StopWatchResponseMsg{
+						ChannelId: id[:],
+						Success:   err == nil,
+						Error:     errStr,
+						Code:      code}}})
+			case *proto.Message_DisputeStatusRequest:
+				var id channel.ID
+				if len(msg.DisputeStatusRequest.ChannelId) != len(id) {
+					errStr, code := errorResponse(fmt.Errorf("%w: invalid channel ID", ErrInvalidRequest), proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_DisputeStatusResponse{
+						DisputeStatusResponse: &proto.DisputeStatusResponseMsg{Error: errStr, Code: code}}})
+					return
+				}
+				copy(id[:], msg.DisputeStatusRequest.ChannelId)
+				resp, err := s.disputeStatus(id)
+				if err != nil {
+					log.Errorf("Reading dispute status failed: %v", err)
+				}
+				resp.ChannelId = id[:]
+				resp.Success = err == nil
+				resp.Error, resp.Code = errorResponse(err, proto.ErrorCode_INTERNAL_ERROR)
+				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_DisputeStatusResponse{
+					DisputeStatusResponse: resp}})
 			case *proto.Message_FundingRequest:
 				log.Warn("Server: Got Funding request")
-				req, err := ParseFundingRequestMsg(msg.FundingRequest)
+				req, err := s.limits.ParseFundingRequestMsg(msg.FundingRequest)
 				if err != nil {
 					log.Errorf("Invalid update message: %v", err)
+					errStr, code := errorResponse(fmt.Errorf("%w: %v", ErrInvalidRequest, err), proto.ErrorCode_INVALID_REQUEST)
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_FundingResponse{
+						FundingResponse: &proto.FundingResponseMsg{Error: errStr, Code: code}}})
 					return
 				}
-				if err := s.funder.Fund(s.Ctx(), channel.FundingReq{
+				sendProgress := func(p FundingProgress) {
+					sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_FundingProgress{
+						FundingProgress: &proto.FundingProgressMsg{
+							ChannelId:  req.InitialState.ID[:],
+							AssetIndex: uint32(p.Asset),
+							Confirmed:  uint32(p.Confirmed),
+							Total:      uint32(p.Total)}}})
+				}
+				ctx := s.Ctx()
+				if req.Timeout > 0 {
+					var cancel context.CancelFunc
+					ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+					defer cancel()
+				}
+				if err = s.funder.Fund(ctx, channel.FundingReq{
 					Params:    &req.Params,
 					State:     &req.InitialState,
 					Idx:       req.Participant,
 					Agreement: req.FundingAgreement,
-				}); err != nil {
+				}, sendProgress); err != nil {
 					log.Errorf("Funding failed: %v", err)
 				}
+				errStr, code := errorResponse(err, proto.ErrorCode_FUNDING_FAILED)
 				sendMsg(&m, conn, &proto.Message{Msg: &proto.Message_FundingResponse{
 					FundingResponse: &proto.FundingResponseMsg{
 						ChannelId: req.InitialState.ID[:],
-						Success:   err == nil}}})
+						Success:   err == nil,
+						Error:     errStr,
+						Code:      code}}})
 			}
-		}()
+		}
+		if key, ok := channelKey(msg); ok {
+			queue.Submit(key, handle)
+		} else {
+			go handle()
+		}
 	}
 }
 
-func recvMsg(conn io.Reader) (*proto.Message, error) {
-	var size uint16
-	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
-		return nil, fmt.Errorf("reading size of data from wire: %w", err)
+// channelKey returns a key identifying the channel a request refers to.
+func channelKey(msg *proto.Message) (string, bool) {
+	var id []byte
+	switch msg := msg.GetMsg().(type) {
+	case *proto.Message_WatchRequest:
+		id = msg.WatchRequest.GetState().GetState().GetId()
+	case *proto.Message_WatchUpdate:
+		id = msg.WatchUpdate.GetRequest().GetState().GetState().GetId()
+	case *proto.Message_ForceCloseRequest:
+		id = msg.ForceCloseRequest.GetChannelId()
+	case *proto.Message_StopWatchRequest:
+		id = msg.StopWatchRequest.GetChannelId()
+	case *proto.Message_FundingRequest:
+		id = msg.FundingRequest.GetInitialState().GetId()
+	case *proto.Message_DisputeStatusRequest:
+		id = msg.DisputeStatusRequest.GetChannelId()
 	}
-	data := make([]byte, size)
-	if _, err := io.ReadFull(conn, data); err != nil {
-		return nil, fmt.Errorf("reading data from wire: %w", err)
+	return string(id), len(id) > 0
+}
+
+// readMessage reads the next message of the client, resetting the idle
+// timeout. Errors are logged, the connection has to be closed on any error.
+func (s *Server) readMessage(conn io.ReadWriteCloser) (*proto.Message, error) {
+	if err := s.resetReadDeadline(conn); err != nil {
+		log.Errorf("setting read deadline: %v", err)
+		return nil, err
 	}
-	var msg proto.Message
-	if err := protobuf.Unmarshal(data, &msg); err != nil {
-		return nil, fmt.Errorf("unmarshalling message: %w", err)
+	msg, err := s.codec.ReadMessage(conn)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		log.Warnf("Server: closing connection idle for %v", s.idleTimeout)
+	} else if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The client closed the connection, possibly in the middle of a
+		// message.
+		log.Debugf("Server: client disconnected: %v", err)
+	} else if err != nil {
+		log.Errorf("decoding message failed: %v", err)
 	}
-	return &msg, nil
+	return msg, err
 }
 
-func sendMsg(m *sync.Mutex, conn io.Writer, msg *proto.Message) error {
-	m.Lock()
-	defer m.Unlock()
-	data, err := protobuf.Marshal(msg)
+// disputeStatusWait is how long the server waits for the adjudicator to report
+// a past event of a channel before considering it undisputed.
+const disputeStatusWait = 5 * time.Second
+
+// disputeStatus reads the on-chain dispute state of the channel. The response
+// is returned without the channel ID and result fields.
+func (s *Server) disputeStatus(id channel.ID) (*proto.DisputeStatusResponseMsg, error) {
+	resp := &proto.DisputeStatusResponseMsg{Phase: proto.DisputePhase_NO_DISPUTE}
+	evt, err := s.watcher.DisputeStatus(s.Ctx(), id, disputeStatusWait)
+	if err != nil || evt == nil {
+		return resp, err
+	}
+	switch evt.(type) {
+	case *channel.RegisteredEvent:
+		resp.Phase = proto.DisputePhase_DISPUTE
+	case *channel.ProgressedEvent:
+		resp.Phase = proto.DisputePhase_FORCE_EXEC
+	case *channel.ConcludedEvent:
+		resp.Phase = proto.DisputePhase_CONCLUDED
+	}
+	resp.Version = evt.Version()
+	resp.TimeoutElapsed = s.watcher.timeoutElapsed(s.Ctx(), evt.Timeout())
+	return resp, nil
+}
+
+// errStatusOnly is returned by handshake if the client sent a status request
+// instead of a handshake.
+var errStatusOnly = errors.New("status request instead of handshake")
+
+// handshake receives the handshake of the client and answers it with the
+// server version. An error is returned (and sent to the client) if the client
+// speaks an incompatible version. Clients which do not start with a handshake
+// speak version 1, their first message is returned to be handled as a request.
+// A status request instead of the handshake is answered and errStatusOnly
+// returned.
+func (s *Server) handshake(m *sync.Mutex, conn io.ReadWriter) (*proto.Message, error) {
+	msg, err := s.codec.ReadMessage(conn)
+	if err != nil {
+		return nil, err
+	}
+	if msg.GetStatusRequest() != nil {
+		if err := s.sendStatus(m, conn); err != nil {
+			return nil, err
+		}
+		return nil, errStatusOnly
+	}
+
+	req := msg.GetHandshakeRequest()
+	if req == nil {
+		return msg, nil
+	}
+
+	if req.Version != ProtocolVersion {
+		err = fmt.Errorf("incompatible protocol version %d, server speaks version %d", req.Version, ProtocolVersion)
+	}
+
+	resp := &proto.HandshakeResponseMsg{Version: ProtocolVersion, Success: err == nil}
 	if err != nil {
-		return fmt.Errorf("marshalling message: %w", err)
+		resp.Error = err.Error()
 	}
-	if err := binary.Write(conn, binary.BigEndian, uint16(len(data))); err != nil {
-		return fmt.Errorf("writing length to wire: %w", err)
+	if sendErr := sendMsg(m, conn, &proto.Message{Msg: &proto.Message_HandshakeResponse{
+		HandshakeResponse: resp}}); sendErr != nil && err == nil {
+		return nil, sendErr
 	}
-	if _, err = conn.Write(data); err != nil {
-		return fmt.Errorf("writing data to wire: %w", err)
+	return nil, err
+}
+
+// sendWatched sends the channels the watcher is watching.
+func (s *Server) sendWatched(m *sync.Mutex, conn io.Writer) error {
+	watched := s.watcher.List()
+	channels := make([]*proto.WatchedChannel, len(watched))
+	for i, ch := range watched {
+		channels[i] = &proto.WatchedChannel{
+			ChannelId:   append([]byte(nil), ch.ID[:]...),
+			Participant: uint32(ch.Idx),
+			Version:     ch.Version,
+			Disputed:    ch.Disputed,
+		}
 	}
-	return nil
+	return sendMsg(m, conn, &proto.Message{Msg: &proto.Message_ListWatchedResponse{
+		ListWatchedResponse: &proto.ListWatchedResponseMsg{Channels: channels}}})
+}
+
+func (s *Server) sendStatus(m *sync.Mutex, conn io.Writer) error {
+	return sendMsg(m, conn, &proto.Message{Msg: &proto.Message_StatusResponse{
+		StatusResponse: &proto.StatusResponseMsg{
+			ProtocolVersion: ProtocolVersion,
+			WatchedChannels: uint64(s.watcher.WatchedChannels()),
+			UptimeSeconds:   uint64(time.Since(s.started).Seconds()),
+		}}})
+}
+
+// sendMsg writes the message, serializing the writers of the connection with
+// m.
+func sendMsg(m *sync.Mutex, conn io.Writer, msg *proto.Message) error {
+	m.Lock()
+	defer m.Unlock()
+	return codec.Codec{}.WriteMessage(conn, msg)
 }
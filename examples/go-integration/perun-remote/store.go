@@ -0,0 +1,83 @@
+package remote
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"perun.network/go-perun/channel"
+)
+
+// WatchStore persists the latest watch request of every watched channel, so
+// that watching can be resumed after a restart (see RestoreWatching). The
+// requests are stored in their protobuf encoding.
+type WatchStore interface {
+	// Put stores the request, replacing the previous one of the channel.
+	Put(id channel.ID, req []byte) error
+	// Delete removes the request of the channel.
+	Delete(id channel.ID) error
+	// All returns all stored requests.
+	All() (map[channel.ID][]byte, error)
+}
+
+// FileWatchStore is a WatchStore keeping one file per channel in a directory.
+type FileWatchStore struct {
+	dir string
+}
+
+var _ WatchStore = (*FileWatchStore)(nil)
+
+const watchFileExt = ".watch"
+
+// NewFileWatchStore creates a store in dir, creating the directory if needed.
+func NewFileWatchStore(dir string) (*FileWatchStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileWatchStore{dir: dir}, nil
+}
+
+func (s *FileWatchStore) path(id channel.ID) string {
+	return filepath.Join(s.dir, hex.EncodeToString(id[:])+watchFileExt)
+}
+
+// Put writes the request to a temporary file first, so that a crash does not
+// leave a partially written request behind.
+func (s *FileWatchStore) Put(id channel.ID, req []byte) error {
+	tmp := s.path(id) + ".tmp"
+	if err := os.WriteFile(tmp, req, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(id))
+}
+
+func (s *FileWatchStore) Delete(id channel.ID) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *FileWatchStore) All() (map[channel.ID][]byte, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+watchFileExt))
+	if err != nil {
+		return nil, err
+	}
+	reqs := make(map[channel.ID][]byte, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), watchFileExt)
+		var id channel.ID
+		b, err := hex.DecodeString(name)
+		if err != nil || len(b) != len(id) {
+			return nil, fmt.Errorf("invalid watch file name: %s", file)
+		}
+		copy(id[:], b)
+		if reqs[id], err = os.ReadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return reqs, nil
+}
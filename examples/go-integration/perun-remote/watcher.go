@@ -1,16 +1,21 @@
 package remote
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	protobuf "google.golang.org/protobuf/proto"
 
 	"perun.network/go-perun/channel"
 	"perun.network/go-perun/wallet"
 	"perun.network/go-perun/watcher"
+
+	"go-integration/perun-remote/proto"
 )
 
 type watchEntry struct {
@@ -18,9 +23,43 @@ type watchEntry struct {
 	Idx    channel.Index
 	watcher.StatesPub
 	watcher.AdjudicatorSub
-	participantAcc      wallet.Account // use PreSignedAccount for secure noncustodial signing
-	latest              channel.Transaction
-	onDisputeRegistered func(*channel.RegisteredEvent)
+	participantAcc wallet.Account // use PreSignedAccount for secure noncustodial signing
+	latest         channel.Transaction
+	receiver       wallet.Address                 // nil for the receiver of the adjudicator
+	onDispute      func(channel.AdjudicatorEvent) // Registered or progressed events
+	// Restored from the store, onDispute is replaced by the next
+	// watch request.
+	restored bool
+	// Set by StopWatch, the channel is not withdrawn.
+	stopped bool
+	// Set on the first registered or progressed event.
+	disputed bool
+	// Set once watching ended, e.g. because the channel concluded. The entry
+	// is kept for withdrawing the parent of a sub-channel.
+	ended bool
+	// Logs with the channel ID attached.
+	logger *log.Entry
+	// Parent of a sub-channel, nil for ledger channels. Sub-channels are
+	// registered and withdrawn together with their parent.
+	parent *channel.ID
+}
+
+// equalSigs returns whether both signature lists are identical.
+func equalSigs(a, b []wallet.Sig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// channelLogger returns a logger attaching the channel ID to every entry.
+func channelLogger(id channel.ID) *log.Entry {
+	return log.WithField("channel", fmt.Sprintf("0x%x", id))
 }
 
 // WatcherService serves a single client, watching and disputing multiple ledger channels.
@@ -29,22 +68,101 @@ type WatcherService struct {
 	watch watcher.Watcher
 
 	watching map[channel.ID]*watchEntry
+	active   int // Number of channels watched until conclusion.
 	adj      channel.Adjudicator
+
+	batcher *withdrawalBatcher // nil if withdrawals are not batched
+	// Creates adjudicators withdrawing to custom receivers, nil if custom
+	// receivers are not supported.
+	receiverAdjs func(receiver wallet.Address) (channel.Adjudicator, error)
+
+	store   WatchStore // nil if not persisted
+	metrics Metrics
+	verify  SigVerifier
+	clock   Clock
+}
+
+// WatcherOption configures optional behavior of a WatcherService.
+type WatcherOption func(*WatcherService)
+
+// WithWithdrawalBatchWindow makes the service collect the withdrawals of
+// channels concluding within the given window and submit them together. This
+// only takes effect if the adjudicator implements BatchWithdrawer, otherwise
+// channels are withdrawn individually. The channels of a failed batch are
+// withdrawn individually, too. A zero window disables batching.
+func WithWithdrawalBatchWindow(window time.Duration) WatcherOption {
+	return func(service *WatcherService) {
+		batchAdj, ok := service.adj.(BatchWithdrawer)
+		if !ok || window <= 0 {
+			service.batcher = nil
+			return
+		}
+		service.batcher = newWithdrawalBatcher(service.adj, batchAdj, window)
+	}
+}
+
+// WithReceiverAdjudicators allows watch requests to specify the receiver of
+// the withdrawn funds. Withdrawals to such receivers are sent via the
+// adjudicator returned by newAdj for the receiver.
+func WithReceiverAdjudicators(newAdj func(receiver wallet.Address) (channel.Adjudicator, error)) WatcherOption {
+	return func(service *WatcherService) {
+		service.receiverAdjs = newAdj
+	}
+}
+
+// WithWatchStore persists the watch requests in the store, so watching can be
+// resumed with RestoreWatching after a restart.
+func WithWatchStore(store WatchStore) WatcherOption {
+	return func(service *WatcherService) {
+		service.store = store
+	}
+}
+
+// WithSigVerifier verifies the signatures of watched states with verify, for
+// deployments whose wallet backend is not the default one.
+func WithSigVerifier(verify SigVerifier) WatcherOption {
+	return func(service *WatcherService) {
+		service.verify = verify
+	}
+}
+
+// WithMetrics reports the events of the service to m.
+func WithMetrics(m Metrics) WatcherOption {
+	return func(service *WatcherService) {
+		service.metrics = m
+	}
 }
 
 func NewWatcherService(
 	watch watcher.Watcher,
 	adj channel.Adjudicator,
+	opts ...WatcherOption,
 ) *WatcherService {
-	return &WatcherService{
+	service := &WatcherService{
 		watch:    watch,
 		watching: make(map[channel.ID]*watchEntry),
-		adj:      adj}
+		adj:      adj,
+		metrics:  noMetrics{},
+		verify:   DefaultSigVerifier,
+		clock:    RealClock}
+	for _, opt := range opts {
+		opt(service)
+	}
+	if service.batcher != nil {
+		service.batcher.clock = service.clock
+	}
+	return service
 }
 
-func (service *WatcherService) Watch(r WatchRequestMsg, onDisputeRegistered func(*channel.RegisteredEvent)) error {
-	if !r.VerifyIntegrity() {
-		return errors.New("invalid request")
+// Watch starts watching the channel or updates the watched state. onDispute is
+// called when the channel is registered or progressed on-chain; when updating,
+// a nil onDispute keeps the current callback.
+func (service *WatcherService) Watch(r WatchRequestMsg, onDispute func(channel.AdjudicatorEvent)) error {
+	if !r.VerifyIntegrity(service.verify) {
+		return ErrInvalidRequest
+	}
+	if r.Receiver != nil && service.receiverAdjs == nil {
+		return fmt.Errorf("%w: custom receivers are not supported", ErrInvalidRequest)
 	}
 
 	id := r.State.State.ID
@@ -55,120 +173,451 @@ func (service *WatcherService) Watch(r WatchRequestMsg, onDisputeRegistered func
 	}
 
 	// register channel if not tracked, otherwise, update.
-	entry, err := func() (_ *watchEntry, _ error) {
+	entry, duplicate, err := func() (_ *watchEntry, duplicate bool, _ error) {
 		service.mutex.Lock()
 		defer service.mutex.Unlock()
 
 		entry, ok := service.watching[id]
 		if ok {
 			if r.State.State.Version < entry.latest.State.Version {
-				return nil, errors.New("registered outdated version")
+				return nil, false, fmt.Errorf("%w: got %d, already watching %d",
+					ErrOutdatedVersion, r.State.State.Version, entry.latest.State.Version)
+			}
+			if entry.restored && onDispute != nil {
+				entry.onDispute = onDispute
+				entry.restored = false
+			}
+			// A retried request, e.g. after a dropped response, must not
+			// publish or register the state again.
+			if r.State.State.Version == entry.latest.State.Version &&
+				equalSigs(r.State.Sigs, entry.latest.Sigs) {
+				return entry, true, nil
+			}
+			if err := service.persist(id, r); err != nil {
+				return nil, false, err
 			}
 
 			entry.latest.State = r.State.State
 			entry.latest.Sigs = r.State.Sigs
 			entry.participantAcc = r.AuthSigner
-			return entry, nil
+			entry.receiver = r.Receiver
+			return entry, false, nil
 		} else {
+			if r.Parent != nil {
+				if _, ok := service.watching[*r.Parent]; !ok {
+					return nil, false, fmt.Errorf("parent 0x%x: %w", *r.Parent, ErrUnknownChannel)
+				}
+			}
+			if err := service.persist(id, r); err != nil {
+				return nil, false, err
+			}
 			// This should ideally happen in another thread / outside of the master mutex lock, but for now it's alright.
-			pub, sub, err := service.watch.StartWatchingLedgerChannel(
-				context.Background(), r.State)
+			var (
+				pub watcher.StatesPub
+				sub watcher.AdjudicatorSub
+				err error
+			)
+			if r.Parent != nil {
+				pub, sub, err = service.watch.StartWatchingSubChannel(
+					context.Background(), *r.Parent, r.State)
+			} else {
+				pub, sub, err = service.watch.StartWatchingLedgerChannel(
+					context.Background(), r.State)
+			}
 			if err != nil {
-				return nil, err
+				service.unpersist(id)
+				return nil, false, err
 			}
 			entry = &watchEntry{
-				Params:              *r.State.Params,
-				Idx:                 r.Participant,
-				StatesPub:           pub,
-				AdjudicatorSub:      sub,
-				participantAcc:      r.AuthSigner,
-				latest:              latestTx,
-				onDisputeRegistered: onDisputeRegistered,
+				Params:         *r.State.Params,
+				Idx:            r.Participant,
+				StatesPub:      pub,
+				AdjudicatorSub: sub,
+				participantAcc: r.AuthSigner,
+				latest:         latestTx,
+				receiver:       r.Receiver,
+				onDispute:      onDispute,
+				logger:         channelLogger(id),
+				parent:         r.Parent,
 			}
 			service.watching[id] = entry
+			service.active++
+			service.metrics.WatchStarted()
 
 			go service.watchAndWithdraw(entry)
-			return entry, nil
+			return entry, false, nil
 		}
 	}()
 	if err != nil {
 		return err
 	}
+	if duplicate {
+		entry.logger.Debugf("Watcher: ignoring repeated request for version %d", r.State.State.Version)
+		return nil
+	}
 
 	err = entry.Publish(context.Background(), latestTx)
 	if err != nil {
-		log.Errorf("Watcher: publishing channel: %v", err)
+		entry.logger.Errorf("Watcher: publishing channel: %v", err)
 	}
 
-	if r.State.State.IsFinal {
-		log.Warn("Final state reached, withdrawing...")
+	if r.State.State.IsFinal && entry.parent == nil {
+		entry.logger.Warn("Final state reached, withdrawing...")
 		err := service.adj.Register(context.Background(), channel.AdjudicatorReq{
 			Params: &entry.Params,
 			Acc:    entry.participantAcc,
 			Tx:     entry.latest,
 			Idx:    entry.Idx,
-		}, nil)
+		}, service.subStates(id))
 
 		if err != nil {
 			return fmt.Errorf("Failed to register final state: %w", err)
 		}
-		log.Warn("Successfully registered final state!")
+		entry.logger.Warn("Successfully registered final state!")
 	}
 
 	return nil
 }
 
-func (service *WatcherService) watchAndWithdraw(e *watchEntry) error {
-	defer service.watch.StopWatching(context.Background(), e.Params.ID())
-	defer log.Warnln("watchAndWithdraw returns.")
-	for evt := range e.EventStream() {
-		// Notify the device as early as possible
-		if event, ok := evt.(*channel.RegisteredEvent); ok {
-			e.onDisputeRegistered(event)
-		}
+// WatchedChannels returns the number of channels currently watched.
+func (service *WatcherService) WatchedChannels() int {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
 
-		if _, ok := evt.(*channel.ConcludedEvent); ok {
-			break
-		} else {
-			log.Warnf("Awaiting timout on adjudicator event: %T", evt)
-			log.Warnf("Wait: %v", evt.Timeout().Wait(context.Background()))
-			log.Warnf("Timeout %T elapsed", evt)
-			break
+	return service.active
+}
+
+// ChannelSummary describes a watched channel.
+type ChannelSummary struct {
+	ID      channel.ID
+	Idx     channel.Index
+	Version uint64 // Version of the latest watched state.
+	// Whether a dispute was seen on-chain.
+	Disputed bool
+}
+
+// List returns the watched channels, sorted by ID. Concluded channels are
+// not listed.
+func (service *WatcherService) List() []ChannelSummary {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	list := make([]ChannelSummary, 0, service.active)
+	for id, entry := range service.watching {
+		if entry.ended {
+			continue
 		}
+		list = append(list, ChannelSummary{
+			ID:       id,
+			Idx:      entry.Idx,
+			Version:  entry.latest.State.Version,
+			Disputed: entry.disputed,
+		})
 	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].ID[:], list[j].ID[:]) < 0
+	})
+	return list
+}
 
-	req := func() channel.AdjudicatorReq {
+// Update updates the state of a watched channel. Unlike Watch, it fails for
+// channels which are not watched and requires a newer version than the
+// watched one.
+func (service *WatcherService) Update(u WatchUpdateMsg) error {
+	if !u.VerifyIntegrity(service.verify) {
+		return ErrInvalidRequest
+	}
+	id := u.State.State.ID
+
+	service.mutex.Lock()
+	entry, ok := service.watching[id]
+	var (
+		idx    channel.Index
+		latest uint64
+	)
+	if ok {
+		idx, latest = entry.Idx, entry.latest.State.Version
+	}
+	service.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("updating: %w", ErrUnknownChannel)
+	}
+	if u.Participant != idx {
+		return fmt.Errorf("%w: participant %d, watching for %d", ErrInvalidRequest, u.Participant, idx)
+	}
+	if u.State.State.Version <= latest {
+		return fmt.Errorf("%w: got %d, already watching %d",
+			ErrOutdatedVersion, u.State.State.Version, latest)
+	}
+
+	return service.Watch(u.WatchRequestMsg, nil)
+}
+
+func (service *WatcherService) watchAndWithdraw(e *watchEntry) error {
+	defer service.watch.StopWatching(context.Background(), e.Params.ID())
+	defer func() {
+		service.mutex.Lock()
+		service.active--
+		e.ended = true
+		service.mutex.Unlock()
+		service.metrics.WatchEnded()
+	}()
+	defer e.logger.Warnln("watchAndWithdraw returns.")
+	if !service.awaitConclusion(e) {
+		return nil
+	}
+	if e.parent != nil {
+		e.logger.Warnf("Sub-channel concluded, its funds are withdrawn with the parent 0x%x", *e.parent)
+		service.unpersist(e.Params.ID())
+		return nil
+	}
+
+	req, receiver := func() (channel.AdjudicatorReq, wallet.Address) {
 		service.mutex.Lock()
 		defer service.mutex.Unlock()
 		return channel.AdjudicatorReq{
 			Params: &e.Params,
 			Acc:    e.participantAcc,
 			Tx:     e.latest,
-			Idx:    e.Idx}
+			Idx:    e.Idx}, e.receiver
 	}()
 
-	log.Warnln("Channel concluded on-chain! withdrawing...")
-	err := service.adj.Withdraw(context.Background(), req, nil)
+	e.logger.Warnln("Channel concluded on-chain! withdrawing...")
+	err := service.withdraw(context.Background(), req, receiver, service.subStateMap(e.Params.ID()))
+
+	if err != nil {
+		service.metrics.WithdrawalFailed()
+		e.logger.Errorf("Failed to withdraw: %v", err)
+		return err
+	}
+	service.metrics.WithdrawalSucceeded()
+	e.logger.Warn("Successfully withdrawn!")
+	service.unpersist(e.Params.ID())
+	return nil
+}
+
+// persist stores the request if a store is configured.
+func (service *WatcherService) persist(id channel.ID, r WatchRequestMsg) error {
+	if service.store == nil || r.raw == nil {
+		return nil
+	}
+	data, err := protobuf.Marshal(r.raw)
+	if err != nil {
+		return fmt.Errorf("marshalling watch request: %w", err)
+	}
+	if err := service.store.Put(id, data); err != nil {
+		return fmt.Errorf("persisting watch request: %w", err)
+	}
+	return nil
+}
+
+func (service *WatcherService) unpersist(id channel.ID) {
+	if service.store == nil {
+		return
+	}
+	if err := service.store.Delete(id); err != nil {
+		channelLogger(id).Errorf("Watcher: deleting persisted request: %v", err)
+	}
+}
 
+// RestoreWatching resumes watching all channels persisted in the store. It
+// should be called once on startup, before serving clients. Dispute
+// notifications of a restored channel are sent to the client which sends the
+// next watch request for it.
+func (service *WatcherService) RestoreWatching() error {
+	if service.store == nil {
+		return nil
+	}
+	reqs, err := service.store.All()
 	if err != nil {
-		log.Errorf("Failed to withdraw: %v", err)
+		return fmt.Errorf("loading persisted watch requests: %w", err)
+	}
+	// Parents have to be watched before their sub-channels.
+	var ledger, sub []*WatchRequestMsg
+	for id, data := range reqs {
+		var msg proto.WatchRequestMsg
+		if err := protobuf.Unmarshal(data, &msg); err != nil {
+			return fmt.Errorf("unmarshalling watch request of 0x%x: %w", id, err)
+		}
+		req, err := ParseWatchRequestMsg(&msg)
+		if err != nil {
+			return fmt.Errorf("parsing watch request of 0x%x: %w", id, err)
+		}
+		if req.Parent == nil {
+			ledger = append(ledger, req)
+		} else {
+			sub = append(sub, req)
+		}
+	}
+	for _, req := range append(ledger, sub...) {
+		id := req.State.State.ID
+		notify := func(evt channel.AdjudicatorEvent) {
+			channelLogger(evt.ID()).Warnf("Watcher: dispute of restored channel at version %d, no client to notify", evt.Version())
+		}
+		if err := service.Watch(*req, notify); err != nil {
+			return fmt.Errorf("restoring watch of 0x%x: %w", id, err)
+		}
+		service.mutex.Lock()
+		if entry, ok := service.watching[id]; ok {
+			entry.restored = true
+		}
+		service.mutex.Unlock()
+		channelLogger(id).Infof("Watcher: restored watching channel at version %d", req.State.State.Version)
 	}
-	log.Warn("Successfully withdrawn!")
 	return nil
 }
 
+// awaitConclusion follows the adjudicator events of the channel until it is
+// concluded on-chain or the timeout of the latest dispute elapsed, so that it
+// can be concluded as part of the withdrawal. Returns false if watching ended
+// before.
+func (service *WatcherService) awaitConclusion(e *watchEntry) bool {
+	var (
+		elapsed    <-chan error // nil while there is no dispute
+		cancelWait = func() {}
+	)
+	defer func() { cancelWait() }()
+
+	events := e.EventStream()
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				service.mutex.Lock()
+				stopped := e.stopped
+				service.mutex.Unlock()
+				if stopped {
+					e.logger.Info("Watcher: stopped watching channel")
+				} else {
+					e.logger.Error("Watcher: event stream closed before conclusion")
+				}
+				return false
+			}
+
+			switch evt.(type) {
+			case *channel.RegisteredEvent, *channel.ProgressedEvent:
+				// Notify the device as early as possible
+				service.mutex.Lock()
+				notify := e.onDispute
+				e.disputed = true
+				service.mutex.Unlock()
+				notify(evt)
+
+				// A newer dispute event replaces the timeout of the previous one.
+				cancelWait()
+				ctx, cancel := context.WithCancel(context.Background())
+				cancelWait = cancel
+				elapsed = service.waitTimeout(ctx, evt.Timeout())
+				e.logger.Warnf("Awaiting timeout on adjudicator event %T at version %d", evt, evt.Version())
+			case *channel.ConcludedEvent:
+				return true
+			}
+		case err := <-elapsed:
+			if err != nil {
+				e.logger.Errorf("Watcher: waiting for timeout: %v", err)
+				elapsed = nil
+				continue
+			}
+			e.logger.Warn("Dispute timeout elapsed")
+			return true
+		}
+	}
+}
+
+// withdraw withdraws the funds of a concluded channel to the receiver (nil
+// for the adjudicator's receiver), batching it with other withdrawals if
+// enabled. Channels with sub-channels are not batched.
+func (service *WatcherService) withdraw(ctx context.Context, req channel.AdjudicatorReq, receiver wallet.Address, subStates channel.StateMap) error {
+	if receiver != nil {
+		adj, err := service.receiverAdjs(receiver)
+		if err != nil {
+			return fmt.Errorf("creating adjudicator for receiver %v: %w", receiver, err)
+		}
+		return adj.Withdraw(ctx, req, subStates)
+	}
+	if service.batcher != nil && len(subStates) == 0 {
+		return service.batcher.Withdraw(ctx, req)
+	}
+	return service.adj.Withdraw(ctx, req, subStates)
+}
+
+// subStates returns the latest signed states of the watched sub-channels of
+// the channel, which are registered together with it.
+func (service *WatcherService) subStates(parent channel.ID) []channel.SignedState {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var states []channel.SignedState
+	for _, e := range service.watching {
+		if e.parent != nil && *e.parent == parent {
+			params := e.Params
+			states = append(states, channel.SignedState{
+				Params: &params,
+				State:  e.latest.State,
+				Sigs:   e.latest.Sigs,
+			})
+		}
+	}
+	return states
+}
+
+// subStateMap returns the latest states of the watched sub-channels of the
+// channel, which are needed for withdrawing it.
+func (service *WatcherService) subStateMap(parent channel.ID) channel.StateMap {
+	states := make(channel.StateMap)
+	for _, s := range service.subStates(parent) {
+		states[s.State.ID] = s.State
+	}
+	return states
+}
+
+// StopWatch stops watching the channel, e.g. because the client settled it.
+// The channel is not withdrawn by the service afterwards.
+func (service *WatcherService) StopWatch(id channel.ID) error {
+	service.mutex.Lock()
+	entry, ok := service.watching[id]
+	if ok {
+		entry.stopped = true
+		delete(service.watching, id)
+	}
+	service.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("stopping watch: %w", ErrUnknownChannel)
+	}
+
+	service.unpersist(id)
+	// Closes the event stream, which ends watchAndWithdraw.
+	return service.watch.StopWatching(context.Background(), id)
+}
+
 func (service *WatcherService) StartDispute(u ForceCloseRequestMsg) error {
 	service.mutex.Lock()
 	entry, ok := service.watching[u.ChannelId]
 	service.mutex.Unlock()
 	if !ok {
-		return errors.New("disputing unknown channel")
+		return fmt.Errorf("disputing: %w", ErrUnknownChannel)
+	}
+	if entry.parent != nil {
+		return fmt.Errorf("%w: sub-channels are disputed with their parent 0x%x", ErrInvalidRequest, *entry.parent)
 	}
 
 	if u.Latest != nil {
-		err := service.Watch(*u.Latest, func(re *channel.RegisteredEvent) {})
+		latest := u.Latest.State.State
+		if latest.ID != u.ChannelId {
+			return fmt.Errorf("%w: latest state belongs to channel 0x%x", ErrInvalidRequest, latest.ID)
+		}
+		service.mutex.Lock()
+		stored := entry.latest.State.Version
+		service.mutex.Unlock()
+		if latest.Version < stored {
+			return fmt.Errorf("%w: refusing to dispute with version %d, already watching %d",
+				ErrOutdatedVersion, latest.Version, stored)
+		}
+
+		// A nil callback keeps the one of the client watching the channel.
+		err := service.Watch(*u.Latest, nil)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("updating latest state: %w", err)
 		}
 		// Do not register twice.
 		if u.Latest.State.State.IsFinal {
@@ -186,12 +635,39 @@ func (service *WatcherService) StartDispute(u ForceCloseRequestMsg) error {
 			Idx:    entry.Idx}
 	}()
 
-	log.Warnln("Registering state for dispute...")
-	err := service.adj.Register(context.Background(), req, nil)
+	entry.logger.Warnln("Registering state for dispute...")
+	err := service.adj.Register(context.Background(), req, service.subStates(u.ChannelId))
 
 	if err != nil {
 		return fmt.Errorf("Failed to dispute: %w", err)
 	}
-	log.Warn("Successfully registered!")
+	service.metrics.DisputeStarted()
+	entry.logger.Warn("Successfully registered!")
 	return nil
 }
+
+// DisputeStatus reads the latest adjudicator event of the channel from the
+// chain, nil if there was no dispute. The channel does not need to be watched.
+// The adjudicator's subscription delivers the latest past event first, so if
+// none arrives within wait, the channel is considered undisputed.
+func (service *WatcherService) DisputeStatus(ctx context.Context, id channel.ID, wait time.Duration) (channel.AdjudicatorEvent, error) {
+	sub, err := service.adj.Subscribe(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to adjudicator events: %w", err)
+	}
+	defer sub.Close()
+
+	events := make(chan channel.AdjudicatorEvent, 1)
+	go func() { events <- sub.Next() }()
+	select {
+	case evt := <-events:
+		if evt == nil {
+			return nil, fmt.Errorf("reading adjudicator events: %v", sub.Err())
+		}
+		return evt, nil
+	case <-service.clock.After(wait):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,257 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	ethwtest "github.com/perun-network/perun-eth-backend/wallet/test"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	"perun.network/go-perun/watcher"
+)
+
+// stubWatcher is a watcher which counts the started channels and published
+// states, without watching the chain.
+type stubWatcher struct {
+	mu        sync.Mutex
+	started   int
+	published []uint64 // Versions of the published states.
+	events    map[channel.ID]chan channel.AdjudicatorEvent
+}
+
+func newStubWatcher() *stubWatcher {
+	return &stubWatcher{events: make(map[channel.ID]chan channel.AdjudicatorEvent)}
+}
+
+func (w *stubWatcher) StartWatchingLedgerChannel(_ context.Context, s channel.SignedState) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.started++
+	events := make(chan channel.AdjudicatorEvent)
+	w.events[s.State.ID] = events
+	return w, stubSub(events), nil
+}
+
+func (w *stubWatcher) StartWatchingSubChannel(ctx context.Context, _ channel.ID, s channel.SignedState) (watcher.StatesPub, watcher.AdjudicatorSub, error) {
+	return w.StartWatchingLedgerChannel(ctx, s)
+}
+
+func (w *stubWatcher) StopWatching(_ context.Context, id channel.ID) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if events, ok := w.events[id]; ok {
+		close(events)
+		delete(w.events, id)
+	}
+	return nil
+}
+
+func (w *stubWatcher) Publish(_ context.Context, tx channel.Transaction) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.published = append(w.published, tx.Version)
+	return nil
+}
+
+func (w *stubWatcher) counts() (started int, published []uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.started, append([]uint64(nil), w.published...)
+}
+
+// emit sends an adjudicator event of the channel to the service.
+func (w *stubWatcher) emit(t *testing.T, evt channel.AdjudicatorEvent) {
+	t.Helper()
+	w.mu.Lock()
+	events, ok := w.events[evt.ID()]
+	w.mu.Unlock()
+	if !ok {
+		t.Fatalf("channel 0x%x not watched", evt.ID())
+	}
+	select {
+	case events <- evt:
+	case <-time.After(time.Second):
+		t.Fatalf("event of channel 0x%x not received", evt.ID())
+	}
+}
+
+type stubSub chan channel.AdjudicatorEvent
+
+func (s stubSub) EventStream() <-chan channel.AdjudicatorEvent { return s }
+func (s stubSub) Err() error                                   { return nil }
+
+// registeringAdjudicator accepts all registrations. Other adjudicator methods
+// are not implemented.
+type registeringAdjudicator struct {
+	channel.Adjudicator
+}
+
+func (registeringAdjudicator) Register(context.Context, channel.AdjudicatorReq, []channel.SignedState) error {
+	return nil
+}
+
+// signedWatchRequest returns a watch request of the first participant for a
+// state of the channel between the accounts, signed by both.
+func signedWatchRequest(t *testing.T, accs []wallet.Account, version uint64) *WatchRequestMsg {
+	t.Helper()
+	return signWatchRequest(t, accs, testChannelState(t, accs, version))
+}
+
+// testChannelState returns an unsigned state of the channel between the
+// accounts.
+func testChannelState(t *testing.T, accs []wallet.Account, version uint64) channel.SignedState {
+	t.Helper()
+	signed := testSignedState(t, 1)
+	parts := []wallet.Address{accs[0].Address(), accs[1].Address()}
+	signed.Params = channel.NewParamsUnsafe(60, parts, channel.NoApp(), big.NewInt(1), true, false)
+	signed.State.ID = signed.Params.ID()
+	signed.State.Version = version
+	return signed
+}
+
+// signWatchRequest signs the state by the accounts and returns a watch request
+// of the first participant for it.
+func signWatchRequest(t *testing.T, accs []wallet.Account, signed channel.SignedState) *WatchRequestMsg {
+	t.Helper()
+	for i, acc := range accs {
+		sig, err := channel.Sign(acc, signed.State)
+		if err != nil {
+			t.Fatalf("signing state: %v", err)
+		}
+		signed.Sigs[i] = sig
+	}
+	req, err := ParseWatchRequestMsg(testWatchRequest(t, signed, 0, 1))
+	if err != nil {
+		t.Fatalf("parsing watch request: %v", err)
+	}
+	return req
+}
+
+func TestStartDisputeKeepsRestoredCallback(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	w := ethwtest.NewTmpWallet()
+	accs := []wallet.Account{w.NewRandomAccount(rng), w.NewRandomAccount(rng)}
+	store, err := NewFileWatchStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("creating store: %v", err)
+	}
+
+	// Watch the channel with one service and restore it with another.
+	first := NewWatcherService(newStubWatcher(), registeringAdjudicator{}, WithWatchStore(store))
+	req := signedWatchRequest(t, accs, 1)
+	id := req.State.State.ID
+	if err := first.Watch(*req, func(channel.AdjudicatorEvent) {}); err != nil {
+		t.Fatalf("watching: %v", err)
+	}
+	watch := newStubWatcher()
+	service := NewWatcherService(watch, registeringAdjudicator{}, WithWatchStore(store))
+	if err := service.RestoreWatching(); err != nil {
+		t.Fatalf("restoring: %v", err)
+	}
+	defer service.StopWatch(id)
+
+	// Disputing does not bind the dispute notifications, the next watch
+	// request of the client does.
+	if err := service.StartDispute(ForceCloseRequestMsg{ChannelId: id, Latest: signedWatchRequest(t, accs, 2)}); err != nil {
+		t.Fatalf("disputing: %v", err)
+	}
+	notified := make(chan uint64, 1)
+	if err := service.Watch(*signedWatchRequest(t, accs, 2), func(evt channel.AdjudicatorEvent) {
+		notified <- evt.Version()
+	}); err != nil {
+		t.Fatalf("watching after the dispute: %v", err)
+	}
+
+	timeout := &channel.TimeTimeout{Time: time.Now().Add(time.Hour)}
+	watch.emit(t, channel.NewRegisteredEvent(id, timeout, 2, nil, nil))
+	select {
+	case version := <-notified:
+		if version != 2 {
+			t.Errorf("notified of version %d, want 2", version)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("client not notified of the dispute")
+	}
+}
+
+func TestWatchDuplicate(t *testing.T) {
+	type request struct {
+		version uint64
+		balance int64 // Balance of the first participant.
+		wantErr error
+	}
+	tests := []struct {
+		name          string
+		requests      []request
+		wantPublished []uint64
+	}{
+		{
+			name:          "identical request",
+			requests:      []request{{1, 10, nil}, {1, 10, nil}},
+			wantPublished: []uint64{1},
+		},
+		{
+			name:          "same version with other signatures",
+			requests:      []request{{1, 10, nil}, {1, 11, nil}},
+			wantPublished: []uint64{1, 1},
+		},
+		{
+			name:          "newer version",
+			requests:      []request{{1, 10, nil}, {2, 10, nil}, {2, 10, nil}},
+			wantPublished: []uint64{1, 2},
+		},
+		{
+			name:          "older version",
+			requests:      []request{{2, 10, nil}, {1, 10, ErrOutdatedVersion}},
+			wantPublished: []uint64{2},
+		},
+	}
+	rng := rand.New(rand.NewSource(1))
+	w := ethwtest.NewTmpWallet()
+	accs := []wallet.Account{w.NewRandomAccount(rng), w.NewRandomAccount(rng)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watch := newStubWatcher()
+			service := NewWatcherService(watch, registeringAdjudicator{})
+
+			var id channel.ID
+			for _, r := range tt.requests {
+				signed := testChannelState(t, accs, r.version)
+				signed.State.Balances[0][0] = big.NewInt(r.balance)
+				req := signWatchRequest(t, accs, signed)
+				id = signed.State.ID
+				if err := service.Watch(*req, func(channel.AdjudicatorEvent) {}); !errors.Is(err, r.wantErr) {
+					t.Fatalf("watching version %d: got error %v, want %v", r.version, err, r.wantErr)
+				}
+			}
+
+			started, published := watch.counts()
+			if started != 1 {
+				t.Errorf("started watching %d times, want once", started)
+			}
+			if !equalVersions(published, tt.wantPublished) {
+				t.Errorf("published versions %v, want %v", published, tt.wantPublished)
+			}
+			if err := service.StopWatch(id); err != nil {
+				t.Fatalf("stopping watch: %v", err)
+			}
+		})
+	}
+}
+
+func equalVersions(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,9 @@ package remote
 import (
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"time"
 
 	"go-integration/perun-remote/proto"
 
@@ -15,19 +18,49 @@ import (
 	perunProto "perun.network/go-perun/wire/protobuf"
 )
 
-func verifySigs(sigs []wallet.Sig, state *channel.State, params channel.Params) bool {
+// SigVerifier verifies the signature of a participant on a state.
+type SigVerifier func(addr wallet.Address, state *channel.State, sig wallet.Sig) (bool, error)
+
+// DefaultSigVerifier verifies signatures with the default wallet backend.
+var DefaultSigVerifier SigVerifier = channel.Verify
+
+func verifySigs(verify SigVerifier, sigs []wallet.Sig, state *channel.State, params channel.Params) bool {
 	if len(sigs) != len(params.Parts) {
 		return false
 	}
 
 	for i, sig := range sigs {
-		ok, _ := channel.Verify(params.Parts[i], state, sig)
+		ok, _ := verify(params.Parts[i], state, sig)
 		if !ok {
 			return false
 		}
 	}
 	return true
 }
+
+var (
+	abiUint256, _ = abi.NewType("uint256", "", nil)
+	abiAddress, _ = abi.NewType("address", "", nil)
+	abiBytes32, _ = abi.NewType("bytes32", "", nil)
+)
+
+// EncodeWithdrawalAuth ABI-encodes the content of the on-chain WithdrawalAuth
+// object, which is the message a participant signs to authorize withdrawing
+// amount from channel id to receiver.
+func EncodeWithdrawalAuth(id channel.ID, participant, receiver wallet.Address, amount *big.Int) ([]byte, error) {
+	args := abi.Arguments{
+		{Type: abiBytes32},
+		{Type: abiAddress},
+		{Type: abiAddress},
+		{Type: abiUint256},
+	}
+	return args.Pack(
+		id,
+		perun_eth_wallet.AsEthAddr(participant),
+		perun_eth_wallet.AsEthAddr(receiver),
+		amount)
+}
+
 func toIdx(i uint32) (channel.Index, error) {
 	if i >= 1<<16 {
 		return 0, errors.New("invalid index")
@@ -39,6 +72,13 @@ type WatchRequestMsg struct {
 	Participant channel.Index
 	State       channel.SignedState
 	AuthSigner  wallet.Account
+	// Receiver of the withdrawn funds, nil to withdraw to the receiver of
+	// the service's adjudicator.
+	Receiver wallet.Address
+	// Parent of a sub-channel, nil for ledger channels.
+	Parent *channel.ID
+
+	raw *proto.WatchRequestMsg // for persisting the request
 }
 
 func ParseWatchRequestMsg(p *proto.WatchRequestMsg) (*WatchRequestMsg, error) {
@@ -51,37 +91,58 @@ func ParseWatchRequestMsg(p *proto.WatchRequestMsg) (*WatchRequestMsg, error) {
 		return nil, err
 	}
 
-	if int(idx) > len(signed.Params.Parts) {
+	if int(idx) >= len(signed.Params.Parts) {
 		return nil, errors.New("Invalid participant index")
 	}
 
+	var parent *channel.ID
+	switch p.Type {
+	case proto.ChannelType_LEDGER:
+	case proto.ChannelType_SUB:
+		parent = new(channel.ID)
+		if len(p.Parent) != len(parent) {
+			return nil, errors.New("invalid parent channel ID")
+		}
+		copy(parent[:], p.Parent)
+	default:
+		return nil, fmt.Errorf("unsupported channel type %v", p.Type)
+	}
+
 	signer := NewPreSignedAccount(signed.Params.Parts[int(idx)])
 
-	var (
-		abiUint256, _ = abi.NewType("uint256", "", nil)
-		abiAddress, _ = abi.NewType("address", "", nil)
-		abiBytes32, _ = abi.NewType("bytes32", "", nil)
-	)
-
-	for i, auth := range p.WithdrawalAuths {
-		args := abi.Arguments{
-			{Type: abiBytes32},
-			{Type: abiAddress},
-			{Type: abiAddress},
-			{Type: abiUint256},
-		}
-		recv := wallet.NewAddress()
-		if err := recv.UnmarshalBinary(auth.Receiver); err != nil {
+	// The asset holder of each asset verifies a separate withdrawal auth of
+	// the participant, over the participant's balance of that asset.
+	balances := signed.State.Allocation.Balances
+	if len(p.WithdrawalAuths) != len(balances) {
+		return nil, fmt.Errorf("got %d withdrawal auths for %d assets",
+			len(p.WithdrawalAuths), len(balances))
+	}
+	var receiver wallet.Address
+	if len(p.Receiver) > 0 {
+		receiver = wallet.NewAddress()
+		if err := receiver.UnmarshalBinary(p.Receiver); err != nil {
 			return nil, fmt.Errorf("decoding receiver address: %w", err)
 		}
-		enc, err := args.Pack(
+	}
+	for asset, auth := range p.WithdrawalAuths {
+		recv := receiver
+		if len(auth.Receiver) > 0 || receiver == nil {
+			recv = wallet.NewAddress()
+			if err := recv.UnmarshalBinary(auth.Receiver); err != nil {
+				return nil, fmt.Errorf("decoding receiver address of asset %d: %w", asset, err)
+			}
+			if receiver != nil && !recv.Equal(receiver) {
+				return nil, fmt.Errorf("receiver of asset %d differs from the requested receiver", asset)
+			}
+		}
+		enc, err := EncodeWithdrawalAuth(
 			signed.State.ID,
-			perun_eth_wallet.AsEthAddr(signer.Address()),
-			perun_eth_wallet.AsEthAddr(recv),
-			signed.State.Allocation.Balances[i][idx])
+			signer.Address(),
+			recv,
+			balances[asset][idx])
 		if err != nil {
 			return nil, fmt.Errorf(
-				"ABI encoding withdrawal auths %d: %w", i, err)
+				"ABI encoding withdrawal auth of asset %d: %w", asset, err)
 		}
 		signer.AddSig(enc, auth.Sig)
 	}
@@ -89,15 +150,44 @@ func ParseWatchRequestMsg(p *proto.WatchRequestMsg) (*WatchRequestMsg, error) {
 	return &WatchRequestMsg{
 		Participant: idx,
 		State:       signed,
-		AuthSigner:  signer}, nil
+		AuthSigner:  signer,
+		Receiver:    receiver,
+		Parent:      parent,
+		raw:         p}, nil
 }
 
-func (r WatchRequestMsg) VerifyIntegrity() bool {
+// VerifyIntegrity checks that the state belongs to the params and is signed
+// by all participants, verifying the signatures with verify.
+func (r WatchRequestMsg) VerifyIntegrity(verify SigVerifier) bool {
 	if r.State.State.ID != r.State.Params.ID() {
 		return false
 	}
 
-	return verifySigs(r.State.Sigs, r.State.State, *r.State.Params)
+	return verifySigs(verify, r.State.Sigs, r.State.State, *r.State.Params)
+}
+
+// WatchUpdateMsg updates the state of a channel which is already watched. The
+// request carries the withdrawal auths over the balances of the new state. Its
+// integrity is verified by the WatcherService.
+type WatchUpdateMsg struct {
+	WatchRequestMsg
+	// BaseVersion is the version the client last sent for the channel.
+	BaseVersion uint64
+}
+
+func ParseWatchUpdateMsg(p *proto.WatchUpdateMsg) (*WatchUpdateMsg, error) {
+	if p.Request == nil {
+		return nil, errors.New("missing watch request")
+	}
+	req, err := ParseWatchRequestMsg(p.Request)
+	if err != nil {
+		return nil, err
+	}
+	if req.State.State.Version <= p.BaseVersion {
+		return nil, fmt.Errorf("%w: got %d, base version is %d",
+			ErrOutdatedVersion, req.State.State.Version, p.BaseVersion)
+	}
+	return &WatchUpdateMsg{WatchRequestMsg: *req, BaseVersion: p.BaseVersion}, nil
 }
 
 type ForceCloseRequestMsg struct {
@@ -124,6 +214,9 @@ type FundingRequestMsg struct {
 	Params           channel.Params
 	InitialState     channel.State
 	FundingAgreement channel.Balances
+	// Timeout is the time the client allows funding to take, zero for the
+	// funder's timeout.
+	Timeout time.Duration
 }
 
 func ParseFundingRequestMsg(p *proto.FundingRequestMsg) (_ *FundingRequestMsg, err error) {
@@ -145,6 +238,38 @@ func ParseFundingRequestMsg(p *proto.FundingRequestMsg) (_ *FundingRequestMsg, e
 	req.InitialState = *initState
 
 	req.FundingAgreement = perunProto.ToBalances(p.FundingAgreement)
+	if err := checkFundingAgreement(req.FundingAgreement, req.InitialState.Allocation.Balances); err != nil {
+		return nil, err
+	}
+
+	if p.TimeoutMs > uint64(math.MaxInt64/time.Millisecond) {
+		return nil, fmt.Errorf("timeout of %d ms out of range", p.TimeoutMs)
+	}
+	req.Timeout = time.Duration(p.TimeoutMs) * time.Millisecond
 
 	return &req, nil
 }
+
+// checkFundingAgreement checks that the agreement distributes the same total
+// per asset as the initial allocation. The funder would otherwise fail only
+// after starting to fund on-chain.
+func checkFundingAgreement(agreement, balances channel.Balances) error {
+	if len(agreement) != len(balances) {
+		return fmt.Errorf("funding agreement has %d assets, allocation has %d",
+			len(agreement), len(balances))
+	}
+	for i := range balances {
+		if len(agreement[i]) != len(balances[i]) {
+			return fmt.Errorf("funding agreement for asset %d has %d participants, allocation has %d",
+				i, len(agreement[i]), len(balances[i]))
+		}
+	}
+	totals := balances.Sum()
+	for i, agreed := range agreement.Sum() {
+		if agreed.Cmp(totals[i]) != 0 {
+			return fmt.Errorf("funding agreement does not match allocation for asset %d: %v != %v",
+				i, agreed, totals[i])
+		}
+	}
+	return nil
+}
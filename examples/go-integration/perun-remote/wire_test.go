@@ -0,0 +1,108 @@
+package remote
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/wallet"
+	perunProto "perun.network/go-perun/wire/protobuf"
+
+	"go-integration/perun-remote/proto"
+)
+
+// testSignedState returns an unsigned two-party ledger channel state over
+// numAssets assets.
+func testSignedState(t *testing.T, numAssets int) channel.SignedState {
+	t.Helper()
+	parts := []wallet.Address{
+		ethwallet.AsWalletAddr(common.Address{1}),
+		ethwallet.AsWalletAddr(common.Address{2}),
+	}
+	params := channel.NewParamsUnsafe(60, parts, channel.NoApp(), big.NewInt(1), true, false)
+
+	assets := make([]channel.Asset, numAssets)
+	for i := range assets {
+		assets[i] = ethchannel.NewAsset(big.NewInt(1337), common.Address{byte(0x10 + i)})
+	}
+	alloc := channel.NewAllocation(len(parts), assets...)
+	for i := range assets {
+		alloc.SetBalance(0, assets[i], big.NewInt(10))
+		alloc.SetBalance(1, assets[i], big.NewInt(20))
+	}
+	return channel.SignedState{
+		Params: params,
+		State: &channel.State{
+			ID:         params.ID(),
+			Version:    1,
+			App:        channel.NoApp(),
+			Allocation: *alloc,
+			Data:       channel.NoData(),
+		},
+		Sigs: make([]wallet.Sig, len(parts)),
+	}
+}
+
+// testWatchRequest returns a watch request of the participant with numAuths
+// withdrawal auths for the state.
+func testWatchRequest(t *testing.T, signed channel.SignedState, participant uint32, numAuths int) *proto.WatchRequestMsg {
+	t.Helper()
+	state, err := perunProto.FromSignedState(&signed)
+	if err != nil {
+		t.Fatalf("encoding signed state: %v", err)
+	}
+	receiver, err := ethwallet.AsWalletAddr(common.Address{0xff}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("encoding receiver: %v", err)
+	}
+	auths := make([]*proto.SignedWithdrawalAuth, numAuths)
+	for i := range auths {
+		auths[i] = &proto.SignedWithdrawalAuth{Sig: []byte{byte(i)}, Receiver: receiver}
+	}
+	return &proto.WatchRequestMsg{
+		Participant:     participant,
+		State:           state,
+		WithdrawalAuths: auths,
+	}
+}
+
+func TestParseWatchRequestMsg(t *testing.T) {
+	tests := []struct {
+		name        string
+		participant uint32
+		numAssets   int
+		numAuths    int
+		wantErr     bool
+	}{
+		{"first participant", 0, 1, 1, false},
+		{"last participant", 1, 1, 1, false},
+		{"participant index equal to count", 2, 1, 1, true},
+		{"participant index above count", 3, 1, 1, true},
+		{"participant index out of range", 1 << 16, 1, 1, true},
+		{"one auth per asset", 0, 3, 3, false},
+		{"missing auth", 0, 3, 2, true},
+		{"no auths", 0, 2, 0, true},
+		{"extra auth", 0, 2, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed := testSignedState(t, tt.numAssets)
+			req, err := ParseWatchRequestMsg(testWatchRequest(t, signed, tt.participant, tt.numAuths))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if req.Participant != channel.Index(tt.participant) {
+				t.Errorf("participant = %d, want %d", req.Participant, tt.participant)
+			}
+			if !req.AuthSigner.Address().Equal(signed.Params.Parts[tt.participant]) {
+				t.Errorf("auth signer %v, want participant %v", req.AuthSigner.Address(), signed.Params.Parts[tt.participant])
+			}
+		})
+	}
+}
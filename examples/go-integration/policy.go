@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wire"
+)
+
+// ProposalPolicy decides which channel proposals are accepted. Constraints
+// which are not set are not checked, so the zero value accepts everything.
+type ProposalPolicy struct {
+	// MinBalance and MaxBalance bound the opening balance of the channel,
+	// i.e., the sum of the initial balances, per asset.
+	MinBalance *big.Int
+	MaxBalance *big.Int
+	// Peers are the accepted proposers.
+	Peers []wire.Address
+	// Assets are the accepted assets.
+	Assets []channel.Asset
+}
+
+// Check returns an error describing why the proposal violates the policy or
+// nil if it is acceptable.
+func (p ProposalPolicy) Check(proposal client.ChannelProposal) error {
+	if len(p.Peers) > 0 {
+		proposer, ok := proposer(proposal)
+		if !ok {
+			return fmt.Errorf("unsupported proposal type %T", proposal)
+		}
+		if !containsPeer(p.Peers, proposer) {
+			return fmt.Errorf("unknown peer %v", proposer)
+		}
+	}
+
+	initBals := proposal.Base().InitBals
+	for i, asset := range initBals.Assets {
+		if len(p.Assets) > 0 && !containsAsset(p.Assets, asset) {
+			return fmt.Errorf("unsupported asset %v", asset)
+		}
+		total := new(big.Int)
+		for _, bal := range initBals.Balances[i] {
+			total.Add(total, bal)
+		}
+		if p.MinBalance != nil && total.Cmp(p.MinBalance) < 0 {
+			return fmt.Errorf("opening balance %v of asset %d below minimum %v", total, i, p.MinBalance)
+		}
+		if p.MaxBalance != nil && total.Cmp(p.MaxBalance) > 0 {
+			return fmt.Errorf("opening balance %v of asset %d above maximum %v", total, i, p.MaxBalance)
+		}
+	}
+	return nil
+}
+
+// proposer returns the wire address of the proposing peer, which is the
+// first peer of ledger and virtual channel proposals.
+func proposer(proposal client.ChannelProposal) (wire.Address, bool) {
+	var peers []wire.Address
+	switch proposal := proposal.(type) {
+	case *client.LedgerChannelProposalMsg:
+		peers = proposal.Peers
+	case *client.VirtualChannelProposalMsg:
+		peers = proposal.Peers
+	}
+	if len(peers) == 0 {
+		return nil, false
+	}
+	return peers[0], true
+}
+
+func containsPeer(peers []wire.Address, peer wire.Address) bool {
+	for _, p := range peers {
+		if p.Equal(peer) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAsset(assets []channel.Asset, asset channel.Asset) bool {
+	for _, a := range assets {
+		if a.Equal(asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatePolicy decides which channel updates proposed by the counterparty are
+// accepted.
+type UpdatePolicy struct {
+	// MinOwnBalance is the balance per asset below which an update must not
+	// decrease our balance. If nil, decreases are not checked.
+	MinOwnBalance *big.Int
+	// MinOwnBalances sets the floor of individual assets by index, replacing
+	// MinOwnBalance for them.
+	MinOwnBalances map[int]*big.Int
+	// AllowFinal allows updates making the state final.
+	AllowFinal bool
+}
+
+// Check returns an error describing why the update from the current state
+// violates the policy or nil if it is acceptable. Our balances are those of
+// all participants except the proposer of the update.
+func (p UpdatePolicy) Check(cur *channel.State, update client.ChannelUpdate) error {
+	next := update.State
+	if len(next.Assets) != len(cur.Assets) {
+		return fmt.Errorf("update changes the number of assets from %d to %d", len(cur.Assets), len(next.Assets))
+	}
+	for i := range cur.Assets {
+		if !cur.Assets[i].Equal(next.Assets[i]) {
+			return fmt.Errorf("update changes asset %d", i)
+		}
+	}
+	if next.IsFinal && !p.AllowFinal {
+		return fmt.Errorf("final updates are not accepted")
+	}
+
+	for i := range cur.Balances {
+		floor := p.floor(i)
+		if floor == nil {
+			continue
+		}
+		for idx := range cur.Balances[i] {
+			if channel.Index(idx) == update.ActorIdx {
+				continue
+			}
+			before, after := cur.Balances[i][idx], next.Balances[i][idx]
+			if after.Cmp(before) < 0 && after.Cmp(floor) < 0 {
+				return fmt.Errorf("update decreases balance of asset %d from %v to %v, below the floor of %v",
+					i, before, after, floor)
+			}
+		}
+	}
+	return nil
+}
+
+// floor returns the minimum own balance of the asset, nil if it has none.
+func (p UpdatePolicy) floor(asset int) *big.Int {
+	if floor, ok := p.MinOwnBalances[asset]; ok {
+		return floor
+	}
+	return p.MinOwnBalance
+}
@@ -0,0 +1,252 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethchannel "github.com/perun-network/perun-eth-backend/channel"
+	ethwallet "github.com/perun-network/perun-eth-backend/wallet"
+	"perun.network/go-perun/channel"
+	"perun.network/go-perun/client"
+	"perun.network/go-perun/wire"
+	"perun.network/go-perun/wire/net/simple"
+)
+
+var (
+	testAlice = simple.NewAddress("Alice")
+	testBob   = simple.NewAddress("Bob")
+	testAsset = ethchannel.NewAsset(big.NewInt(1337), common.Address{0x10})
+)
+
+// testProposal returns a ledger channel proposal of proposer to Bob with the
+// balances of the proposer and Bob in each of the assets.
+func testProposal(t *testing.T, proposer wire.Address, assets []channel.Asset, bals ...int64) *client.LedgerChannelProposalMsg {
+	t.Helper()
+	alloc := channel.NewAllocation(2, assets...)
+	for _, asset := range assets {
+		alloc.SetAssetBalances(asset, []channel.Bal{big.NewInt(bals[0]), big.NewInt(bals[1])})
+	}
+	prop, err := client.NewLedgerChannelProposal(60, ethwallet.AsWalletAddr(common.Address{1}), alloc,
+		[]wire.Address{proposer, testBob})
+	if err != nil {
+		t.Fatalf("creating proposal: %v", err)
+	}
+	return prop
+}
+
+func TestProposalPolicyCheck(t *testing.T) {
+	otherAsset := ethchannel.NewAsset(big.NewInt(1337), common.Address{0x11})
+	ethOnly := []channel.Asset{testAsset}
+
+	tests := []struct {
+		name     string
+		policy   ProposalPolicy
+		proposal client.ChannelProposal
+		wantErr  string // Substring of the error, empty for none.
+	}{
+		{
+			name:     "zero policy",
+			proposal: testProposal(t, testAlice, []channel.Asset{testAsset, otherAsset}, 0, 1000),
+		},
+		{
+			name:     "known peer",
+			policy:   ProposalPolicy{Peers: []wire.Address{testBob, testAlice}},
+			proposal: testProposal(t, testAlice, ethOnly, 10, 10),
+		},
+		{
+			name:     "unknown peer",
+			policy:   ProposalPolicy{Peers: []wire.Address{testBob}},
+			proposal: testProposal(t, testAlice, ethOnly, 10, 10),
+			wantErr:  "unknown peer",
+		},
+		{
+			name:     "supported asset",
+			policy:   ProposalPolicy{Assets: ethOnly},
+			proposal: testProposal(t, testAlice, ethOnly, 10, 10),
+		},
+		{
+			name:     "unsupported asset",
+			policy:   ProposalPolicy{Assets: ethOnly},
+			proposal: testProposal(t, testAlice, []channel.Asset{testAsset, otherAsset}, 10, 10),
+			wantErr:  "unsupported asset",
+		},
+		{
+			name:     "opening balance at the bounds",
+			policy:   ProposalPolicy{MinBalance: big.NewInt(20), MaxBalance: big.NewInt(20)},
+			proposal: testProposal(t, testAlice, ethOnly, 5, 15),
+		},
+		{
+			name:     "opening balance below minimum",
+			policy:   ProposalPolicy{MinBalance: big.NewInt(21)},
+			proposal: testProposal(t, testAlice, ethOnly, 5, 15),
+			wantErr:  "below minimum",
+		},
+		{
+			name:     "opening balance above maximum",
+			policy:   ProposalPolicy{MaxBalance: big.NewInt(19)},
+			proposal: testProposal(t, testAlice, ethOnly, 5, 15),
+			wantErr:  "above maximum",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.proposal)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("rejected acceptable proposal: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testState returns a two-party state with the balances of both participants
+// in each of the assets.
+func testState(assets []channel.Asset, bals ...int64) *channel.State {
+	alloc := channel.NewAllocation(2, assets...)
+	for _, asset := range assets {
+		alloc.SetAssetBalances(asset, []channel.Bal{big.NewInt(bals[0]), big.NewInt(bals[1])})
+	}
+	return &channel.State{Allocation: *alloc}
+}
+
+func TestUpdatePolicyCheck(t *testing.T) {
+	otherAsset := ethchannel.NewAsset(big.NewInt(1337), common.Address{0x11})
+	ethOnly := []channel.Asset{testAsset}
+	cur := testState(ethOnly, 10, 10)
+	final := testState(ethOnly, 10, 10)
+	final.IsFinal = true
+
+	tests := []struct {
+		name    string
+		policy  UpdatePolicy
+		next    *channel.State
+		wantErr string // Substring of the error, empty for none.
+	}{
+		{
+			name:   "increase of our balance",
+			policy: UpdatePolicy{MinOwnBalance: big.NewInt(8)},
+			next:   testState(ethOnly, 15, 5),
+		},
+		{
+			name:   "decrease to the floor",
+			policy: UpdatePolicy{MinOwnBalance: big.NewInt(8)},
+			next:   testState(ethOnly, 8, 12),
+		},
+		{
+			name:    "decrease below the floor",
+			policy:  UpdatePolicy{MinOwnBalance: big.NewInt(8)},
+			next:    testState(ethOnly, 7, 13),
+			wantErr: "below the floor",
+		},
+		{
+			name: "decrease without floor",
+			next: testState(ethOnly, 0, 20),
+		},
+		{
+			name:   "decrease of the proposer's balance",
+			policy: UpdatePolicy{MinOwnBalance: big.NewInt(8)},
+			next:   testState(ethOnly, 17, 3),
+		},
+		{
+			name:    "final update",
+			next:    final,
+			wantErr: "final",
+		},
+		{
+			name:   "allowed final update",
+			policy: UpdatePolicy{AllowFinal: true},
+			next:   final,
+		},
+		{
+			name:    "added asset",
+			next:    testState([]channel.Asset{testAsset, otherAsset}, 10, 10),
+			wantErr: "number of assets",
+		},
+		{
+			name:    "changed asset",
+			next:    testState([]channel.Asset{otherAsset}, 10, 10),
+			wantErr: "changes asset 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The counterparty proposes the update, we are participant 0.
+			err := tt.policy.Check(cur, client.ChannelUpdate{State: tt.next, ActorIdx: 1})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("rejected acceptable update: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdatePolicyAssetFloors(t *testing.T) {
+	otherAsset := ethchannel.NewAsset(big.NewInt(1337), common.Address{0x11})
+	assets := []channel.Asset{testAsset, otherAsset}
+	cur := testState(assets, 10, 10)
+	// next returns a state with our balances in both assets, the counterparty
+	// receiving what we lose.
+	next := func(own0, own1 int64) *channel.State {
+		s := testState(assets, own0, 20-own0)
+		s.Balances[1] = []channel.Bal{big.NewInt(own1), big.NewInt(20 - own1)}
+		return s
+	}
+
+	tests := []struct {
+		name    string
+		policy  UpdatePolicy
+		next    *channel.State
+		wantErr string // Substring of the error, empty for none.
+	}{
+		{
+			name:   "asset floor replaces the default floor",
+			policy: UpdatePolicy{MinOwnBalance: big.NewInt(8), MinOwnBalances: map[int]*big.Int{1: big.NewInt(2)}},
+			next:   next(8, 3),
+		},
+		{
+			name:    "default floor applies to other assets",
+			policy:  UpdatePolicy{MinOwnBalance: big.NewInt(8), MinOwnBalances: map[int]*big.Int{1: big.NewInt(2)}},
+			next:    next(7, 10),
+			wantErr: "asset 0",
+		},
+		{
+			name:    "decrease below the asset floor",
+			policy:  UpdatePolicy{MinOwnBalances: map[int]*big.Int{1: big.NewInt(5)}},
+			next:    next(10, 4),
+			wantErr: "asset 1",
+		},
+		{
+			name:   "asset without floor",
+			policy: UpdatePolicy{MinOwnBalances: map[int]*big.Int{1: big.NewInt(5)}},
+			next:   next(0, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(cur, client.ChannelUpdate{State: tt.next, ActorIdx: 1})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("rejected acceptable update: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
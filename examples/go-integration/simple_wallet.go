@@ -4,26 +4,91 @@ package main
 
 import (
 	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/tyler-smith/go-bip39"
 )
 
+// NewSimpleWallet creates an empty wallet with a random master seed for
+// deriving accounts.
 func NewSimpleWallet() *SimpleWallet {
+	seed := make([]byte, 64)
+	if _, err := rand.Read(seed); err != nil {
+		panic(err)
+	}
+	return newSimpleWalletWithSeed(seed)
+}
+
+// walletCount numbers the wallets of the process for their URLs.
+var walletCount atomic.Uint64
+
+func newSimpleWalletWithSeed(seed []byte) *SimpleWallet {
 	return &SimpleWallet{
+		url:      accounts.URL{Scheme: "simplewallet", Path: strconv.FormatUint(walletCount.Add(1), 10)},
 		accounts: make([]accounts.Account, 0),
 		keys:     make(map[common.Address]*ecdsa.PrivateKey, 0),
+		derived:  make(map[string]accounts.Account),
+		seed:     seed,
+	}
+}
+
+// NewSimpleWalletFromMnemonic creates a wallet with the first count accounts
+// derived from the BIP-39 mnemonic along m/44'/60'/0'/0/i.
+func NewSimpleWalletFromMnemonic(mnemonic string, count int) (*SimpleWallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
 	}
+	w := newSimpleWalletWithSeed(seed)
+	for i := 0; i < count; i++ {
+		path := make(accounts.DerivationPath, len(accounts.DefaultBaseDerivationPath))
+		copy(path, accounts.DefaultBaseDerivationPath)
+		path[len(path)-1] = uint32(i)
+
+		if _, err := w.Derive(path, true); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
 }
 
+// SimpleWallet is safe for concurrent use.
 type SimpleWallet struct {
+	mu       sync.RWMutex
+	url      accounts.URL
 	accounts []accounts.Account
 	keys     map[common.Address]*ecdsa.PrivateKey
 	derived  map[string]accounts.Account
+	// seed is the BIP-39 seed from which accounts are derived.
+	seed []byte
+	// locked holds keystore files which were loaded but not yet decrypted.
+	locked [][]byte
+	// closed is set by Close, no signatures are made until the next Open.
+	closed bool
+	// passphraseHash is the SHA-256 hash of the passphrase of the last Open,
+	// nil before. The *WithPassphrase methods require it.
+	passphraseHash []byte
 }
 
 var _ accounts.Wallet = (*SimpleWallet)(nil)
@@ -33,6 +98,9 @@ func (w *SimpleWallet) GenerateNewAccount() accounts.Account {
 	if err != nil {
 		panic(err)
 	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	return w.add(sk)
 }
 
@@ -41,32 +109,177 @@ func (w *SimpleWallet) ImportFromSecretKeyHex(hexkey string) accounts.Account {
 	if err != nil {
 		panic(err)
 	}
+	return w.ImportFromECDSA(sk)
+}
+
+// ImportFromECDSA adds the secp256k1 key to the wallet.
+func (w *SimpleWallet) ImportFromECDSA(sk *ecdsa.PrivateKey) accounts.Account {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
 	return w.add(sk)
 }
 
+// ImportFromPEM adds the secp256k1 key of the PEM block to the wallet. Both
+// SEC 1 ("EC PRIVATE KEY") and PKCS #8 ("PRIVATE KEY") encodings are
+// supported.
+func (w *SimpleWallet) ImportFromPEM(pemBytes []byte) (accounts.Account, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return accounts.Account{}, errors.New("no PEM block found")
+	}
+	var (
+		sk  *ecdsa.PrivateKey
+		err error
+	)
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		sk, err = parseSEC1Key(block.Bytes, nil)
+	case "PRIVATE KEY":
+		sk, err = parsePKCS8Key(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	return w.ImportFromECDSA(sk), nil
+}
+
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// The x509 package does not support secp256k1, so the keys are parsed here.
+type sec1Key struct {
+	Version    int
+	PrivateKey []byte
+	Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+type pkcs8Key struct {
+	Version   int
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	PrivateKey []byte
+}
+
+// parseSEC1Key parses a SEC 1 encoded secp256k1 key. The curve is taken from
+// the key if curve is nil.
+func parseSEC1Key(der []byte, curve asn1.ObjectIdentifier) (*ecdsa.PrivateKey, error) {
+	var key sec1Key
+	if _, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("parsing EC private key: %w", err)
+	}
+	if curve == nil {
+		curve = key.Curve
+	}
+	if !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("unsupported curve %v, expected secp256k1", curve)
+	}
+	return crypto.ToECDSA(key.PrivateKey)
+}
+
+func parsePKCS8Key(der []byte) (*ecdsa.PrivateKey, error) {
+	var key pkcs8Key
+	if _, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("parsing PKCS #8 private key: %w", err)
+	}
+	if !key.Algorithm.Algorithm.Equal(oidECPublicKey) {
+		return nil, fmt.Errorf("unsupported key algorithm %v, expected EC", key.Algorithm.Algorithm)
+	}
+	return parseSEC1Key(key.PrivateKey, key.Algorithm.Parameters)
+}
+
+// add adds the key to the wallet. The caller must hold the write lock.
 func (w *SimpleWallet) add(sk *ecdsa.PrivateKey) accounts.Account {
 	addr := crypto.PubkeyToAddress(sk.PublicKey)
-	account := accounts.Account{Address: addr}
+	account := accounts.Account{Address: addr, URL: w.url}
+	if _, ok := w.keys[addr]; ok {
+		return account
+	}
 
 	w.accounts = append(w.accounts, account)
 	w.keys[addr] = sk
 	return account
 }
 
-// Accounts implements accounts.Wallet
+// RemoveAccount removes the account and its key from the wallet, zeroing the
+// key first. Derived accounts are derived anew on the next Derive.
+func (w *SimpleWallet) RemoveAccount(addr common.Address) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sk, ok := w.keys[addr]
+	if !ok {
+		return accounts.ErrUnknownAccount
+	}
+	bits := sk.D.Bits()
+	for i := range bits {
+		bits[i] = 0
+	}
+	delete(w.keys, addr)
+
+	for i := range w.accounts {
+		if w.accounts[i].Address == addr {
+			w.accounts = append(w.accounts[:i], w.accounts[i+1:]...)
+			break
+		}
+	}
+	for path, acc := range w.derived {
+		if acc.Address == addr {
+			delete(w.derived, path)
+		}
+	}
+	return nil
+}
+
+// key returns the private key of the account.
+func (w *SimpleWallet) key(account accounts.Account) (*ecdsa.PrivateKey, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return nil, accounts.ErrWalletClosed
+	}
+	sk, ok := w.keys[account.Address]
+	if !ok {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return sk, nil
+}
+
+// Accounts implements accounts.Wallet. The accounts are returned in the order
+// in which they were added or derived. The returned slice is a copy and may
+// be modified by the caller.
 func (w *SimpleWallet) Accounts() []accounts.Account {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	cpy := make([]accounts.Account, len(w.accounts))
 	copy(cpy, w.accounts)
-	return w.accounts
+	return cpy
 }
 
-// Close implements accounts.Wallet
+// Close implements accounts.Wallet. The wallet refuses to sign until it is
+// opened again.
 func (w *SimpleWallet) Close() error {
-	panic("unimplemented")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	return nil
 }
 
 // Contains implements accounts.Wallet
 func (w *SimpleWallet) Contains(account accounts.Account) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	for i := 0; i < len(w.accounts); i++ {
 		if w.accounts[i].Address == account.Address {
 			return true
@@ -75,18 +288,106 @@ func (w *SimpleWallet) Contains(account accounts.Account) bool {
 	return false
 }
 
-// Derive implements accounts.Wallet
+// Derive implements accounts.Wallet. It derives the account at the path from
+// the master seed of the wallet; pinned accounts are added to the wallet.
 func (w *SimpleWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
-	acc, ok := w.derived[path.String()]
-	if !ok {
-		acc = w.GenerateNewAccount()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if acc, ok := w.derived[path.String()]; ok {
+		return acc, nil
+	}
+	sk, err := deriveKey(w.seed, path)
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("deriving %v: %w", path, err)
+	}
+	if !pin {
+		return accounts.Account{Address: crypto.PubkeyToAddress(sk.PublicKey), URL: w.url}, nil
 	}
+	acc := w.add(sk)
+	w.derived[path.String()] = acc
 	return acc, nil
 }
 
-// Open implements accounts.Wallet
-func (*SimpleWallet) Open(passphrase string) error {
-	panic("unimplemented")
+// SaveKeystore writes the keys of all accounts as V3 keystore files into
+// dir, encrypted with the passphrase.
+func (w *SimpleWallet) SaveKeystore(dir, passphrase string) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	for _, acc := range w.accounts {
+		key := &keystore.Key{
+			Id:         uuid.New(),
+			Address:    acc.Address,
+			PrivateKey: w.keys[acc.Address],
+		}
+		data, err := keystore.EncryptKey(key, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			return fmt.Errorf("encrypting key of %v: %w", acc.Address, err)
+		}
+		path := filepath.Join(dir, strings.ToLower(acc.Address.Hex()[2:])+".json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadKeystore loads the keystore files in dir and adds their keys to the
+// wallet, decrypting them with the passphrase (see Open).
+func (w *SimpleWallet) LoadKeystore(dir, passphrase string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	w.mu.Lock()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			w.mu.Unlock()
+			return err
+		}
+		w.locked = append(w.locked, data)
+	}
+	w.mu.Unlock()
+
+	return w.Open(passphrase)
+}
+
+// Open implements accounts.Wallet. It reopens a closed wallet, decrypts the
+// loaded keystore files with the passphrase and adds their keys to the wallet.
+// Files which cannot be decrypted remain locked.
+func (w *SimpleWallet) Open(passphrase string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = false
+	hash := sha256.Sum256([]byte(passphrase))
+	w.passphraseHash = hash[:]
+
+	var (
+		locked  [][]byte
+		openErr error
+	)
+	for _, data := range w.locked {
+		key, err := keystore.DecryptKey(data, passphrase)
+		if err != nil {
+			locked = append(locked, data)
+			openErr = err
+			continue
+		}
+		w.add(key.PrivateKey)
+	}
+	w.locked = locked
+	if openErr != nil {
+		return fmt.Errorf("decrypting keystore: %w", openErr)
+	}
+	return nil
 }
 
 // SelfDerive implements accounts.Wallet
@@ -96,43 +397,95 @@ func (*SimpleWallet) SelfDerive(bases []accounts.DerivationPath, chain ethereum.
 
 // SignData implements accounts.Wallet
 func (w *SimpleWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	sk, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
 	hash := crypto.Keccak256(data)
-	return crypto.Sign(hash, w.keys[account.Address])
+	return crypto.Sign(hash, sk)
 }
 
-// SignDataWithPassphrase implements accounts.Wallet
-func (*SimpleWallet) SignDataWithPassphrase(account accounts.Account, passphrase string, mimeType string, data []byte) ([]byte, error) {
-	panic("unimplemented")
+// checkPassphrase returns an error if the passphrase differs from the one
+// the wallet was opened with.
+func (w *SimpleWallet) checkPassphrase(passphrase string) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.passphraseHash == nil {
+		return errors.New("no passphrase set, open the wallet first")
+	}
+	hash := sha256.Sum256([]byte(passphrase))
+	if subtle.ConstantTimeCompare(hash[:], w.passphraseHash) != 1 {
+		return keystore.ErrDecrypt
+	}
+	return nil
+}
+
+// SignDataWithPassphrase implements accounts.Wallet. The passphrase has to
+// match the one the wallet was opened with.
+func (w *SimpleWallet) SignDataWithPassphrase(account accounts.Account, passphrase string, mimeType string, data []byte) ([]byte, error) {
+	if err := w.checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	return w.SignData(account, mimeType, data)
 }
 
 // SignText implements accounts.Wallet
 func (w *SimpleWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	sk, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
 	hash := accounts.TextHash(text)
-	return crypto.Sign(hash, w.keys[account.Address])
+	return crypto.Sign(hash, sk)
 }
 
-// SignTextWithPassphrase implements accounts.Wallet
-func (*SimpleWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
-	panic("unimplemented")
+// SignTextWithPassphrase implements accounts.Wallet. The passphrase has to
+// match the one the wallet was opened with.
+func (w *SimpleWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	if err := w.checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	return w.SignText(account, text)
 }
 
 // SignTx implements accounts.Wallet
 func (w *SimpleWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	sk, err := w.key(account)
+	if err != nil {
+		return nil, err
+	}
 	signer := types.NewLondonSigner(chainID)
-	return types.SignTx(tx, signer, w.keys[account.Address])
+	return types.SignTx(tx, signer, sk)
 }
 
-// SignTxWithPassphrase implements accounts.Wallet
-func (*SimpleWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	panic("unimplemented")
+// SignTxWithPassphrase implements accounts.Wallet. The passphrase has to
+// match the one the wallet was opened with.
+func (w *SimpleWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if err := w.checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	return w.SignTx(account, tx, chainID)
 }
 
-// Status implements accounts.Wallet
-func (*SimpleWallet) Status() (string, error) {
-	panic("unimplemented")
+// Status implements accounts.Wallet. It returns "Locked" after Close, "Empty"
+// if the wallet holds no accounts and "Unlocked" otherwise.
+func (w *SimpleWallet) Status() (string, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	switch {
+	case w.closed:
+		return "Locked", nil
+	case len(w.accounts) == 0:
+		return "Empty", nil
+	default:
+		return "Unlocked", nil
+	}
 }
 
-// URL implements accounts.Wallet
-func (*SimpleWallet) URL() accounts.URL {
-	panic("unimplemented")
+// URL implements accounts.Wallet. The URL identifies the wallet within the
+// process.
+func (w *SimpleWallet) URL() accounts.URL {
+	return w.url
 }
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNewSimpleWalletFromMnemonic(t *testing.T) {
+	w, err := NewSimpleWalletFromMnemonic(testMnemonic, 2)
+	if err != nil {
+		t.Fatalf("creating wallet: %v", err)
+	}
+	want := []common.Address{
+		common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"),
+		common.HexToAddress("0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"),
+	}
+	accs := w.Accounts()
+	if len(accs) != len(want) {
+		t.Fatalf("%d accounts, want %d", len(accs), len(want))
+	}
+	for i, acc := range accs {
+		if acc.Address != want[i] {
+			t.Errorf("account %d is %v, want %v", i, acc.Address, want[i])
+		}
+	}
+}
+
+func TestNewSimpleWalletFromInvalidMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+	}{
+		{"invalid checksum", strings.Repeat("abandon ", 11) + "abandon"},
+		{"unknown word", strings.Repeat("abandon ", 11) + "perun"},
+		{"wrong length", "abandon abandon about"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSimpleWalletFromMnemonic(tt.mnemonic, 1); err == nil {
+				t.Fatal("no error for an invalid mnemonic")
+			}
+		})
+	}
+}
+
+func TestSimpleWalletDerive(t *testing.T) {
+	w, err := NewSimpleWalletFromMnemonic(testMnemonic, 1)
+	if err != nil {
+		t.Fatalf("creating wallet: %v", err)
+	}
+	path := accounts.DefaultBaseDerivationPath
+	second := make(accounts.DerivationPath, len(path))
+	copy(second, path)
+	second[len(second)-1] = 1
+	wantSecond := common.HexToAddress("0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0")
+
+	// Already derived by the constructor.
+	acc, err := w.Derive(path, true)
+	if err != nil {
+		t.Fatalf("deriving %v: %v", path, err)
+	}
+	if acc != w.Accounts()[0] || len(w.Accounts()) != 1 {
+		t.Errorf("derived %v again, accounts %v", acc.Address, w.Accounts())
+	}
+
+	unpinned, err := w.Derive(second, false)
+	if err != nil {
+		t.Fatalf("deriving %v: %v", second, err)
+	}
+	if unpinned.Address != wantSecond || w.Contains(unpinned) {
+		t.Errorf("unpinned account %v, contained: %t", unpinned.Address, w.Contains(unpinned))
+	}
+
+	pinned, err := w.Derive(second, true)
+	if err != nil {
+		t.Fatalf("deriving %v: %v", second, err)
+	}
+	if pinned.Address != wantSecond || !w.Contains(pinned) || len(w.Accounts()) != 2 {
+		t.Errorf("pinned account %v, accounts %v", pinned.Address, w.Accounts())
+	}
+
+	// The same seed derives the same accounts.
+	other := newSimpleWalletWithSeed(w.seed)
+	if acc, err := other.Derive(second, false); err != nil || acc.Address != wantSecond {
+		t.Errorf("derived %v, %v from the same seed", acc.Address, err)
+	}
+}
+
+func TestSimpleWalletAccountsCopy(t *testing.T) {
+	w := NewSimpleWallet()
+	acc := w.GenerateNewAccount()
+
+	accs := w.Accounts()
+	accs[0] = accounts.Account{}
+
+	got := w.Accounts()
+	if len(got) != 1 || got[0] != acc {
+		t.Errorf("accounts %v after modifying a returned slice, want [%v]", got, acc)
+	}
+}
+
+func TestSimpleWalletStatusAndClose(t *testing.T) {
+	w := NewSimpleWallet()
+	if status, err := w.Status(); err != nil || status != "Empty" {
+		t.Errorf("status %q, %v of an empty wallet", status, err)
+	}
+	acc := w.GenerateNewAccount()
+	if status, err := w.Status(); err != nil || status != "Unlocked" {
+		t.Errorf("status %q, %v with an account", status, err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing wallet: %v", err)
+	}
+	if status, err := w.Status(); err != nil || status != "Locked" {
+		t.Errorf("status %q, %v after Close", status, err)
+	}
+	if _, err := w.SignText(acc, []byte("text")); !errors.Is(err, accounts.ErrWalletClosed) {
+		t.Errorf("signing after Close: got error %v, want %v", err, accounts.ErrWalletClosed)
+	}
+
+	if err := w.Open(""); err != nil {
+		t.Fatalf("opening wallet: %v", err)
+	}
+	if status, err := w.Status(); err != nil || status != "Unlocked" {
+		t.Errorf("status %q, %v after Open", status, err)
+	}
+	if _, err := w.SignText(acc, []byte("text")); err != nil {
+		t.Errorf("signing after Open: %v", err)
+	}
+}
+
+func TestSimpleWalletRemoveAccount(t *testing.T) {
+	w, err := NewSimpleWalletFromMnemonic(testMnemonic, 1)
+	if err != nil {
+		t.Fatalf("creating wallet: %v", err)
+	}
+	derived := w.Accounts()[0]
+	generated := w.GenerateNewAccount()
+
+	if err := w.RemoveAccount(generated.Address); err != nil {
+		t.Fatalf("removing account: %v", err)
+	}
+	if w.Contains(generated) || len(w.Accounts()) != 1 {
+		t.Errorf("accounts %v after removing %v", w.Accounts(), generated.Address)
+	}
+	if _, err := w.SignText(generated, []byte("text")); !errors.Is(err, accounts.ErrUnknownAccount) {
+		t.Errorf("signing with a removed account: got error %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+	if err := w.RemoveAccount(generated.Address); !errors.Is(err, accounts.ErrUnknownAccount) {
+		t.Errorf("removing twice: got error %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+
+	// A removed derived account is derived anew.
+	if err := w.RemoveAccount(derived.Address); err != nil {
+		t.Fatalf("removing derived account: %v", err)
+	}
+	acc, err := w.Derive(accounts.DefaultBaseDerivationPath, true)
+	if err != nil {
+		t.Fatalf("deriving again: %v", err)
+	}
+	if acc != derived || !w.Contains(acc) {
+		t.Errorf("derived %v again, contained: %t", acc.Address, w.Contains(acc))
+	}
+	if _, err := w.SignText(acc, []byte("text")); err != nil {
+		t.Errorf("signing with the derived account: %v", err)
+	}
+}
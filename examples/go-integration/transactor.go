@@ -3,19 +3,45 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-integration/control"
 )
 
 // ChainIdAwareTransactor can be used to make TransactOpts for accounts stored in a HD wallet.
 type ChainIdAwareTransactor struct {
 	Wallet  accounts.Wallet
 	ChainId *big.Int
+	// Fees sets the EIP-1559 fees of the transactions. If nil, go-ethereum
+	// picks the fees.
+	Fees FeeOracle
+	// Nonces assigns the nonces of the transactions. If nil, go-ethereum
+	// uses the pending nonce of the account.
+	Nonces *NonceManager
+}
+
+// FeeOracle suggests the fees of EIP-1559 (type 2) transactions.
+type FeeOracle interface {
+	SuggestFees(ctx context.Context) (gasFeeCap, gasTipCap *big.Int, err error)
+}
+
+// StaticFees is a FeeOracle always suggesting the same fees.
+type StaticFees struct {
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+}
+
+// SuggestFees implements FeeOracle.
+func (f StaticFees) SuggestFees(context.Context) (*big.Int, *big.Int, error) {
+	return new(big.Int).Set(f.GasFeeCap), new(big.Int).Set(f.GasTipCap), nil
 }
 
 // NewTransactor returns a TransactOpts for the given account. It errors if the account is
@@ -24,16 +50,86 @@ func (t *ChainIdAwareTransactor) NewTransactor(account accounts.Account) (*bind.
 	if !t.Wallet.Contains(account) {
 		return nil, errors.New("account not found in wallet")
 	}
-	return &bind.TransactOpts{
-		From: account.Address,
-		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			if address != account.Address {
-				return nil, errors.New("not authorized to sign this account")
+	opts := &bind.TransactOpts{From: account.Address}
+	opts.Signer = func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != account.Address {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		// The backend sets the context of the operation after creating the
+		// options, so the overrides are only known when signing.
+		if opts.Context != nil {
+			if o, ok := control.GasOverridesFrom(opts.Context); ok {
+				tx = applyGasOverrides(tx, o)
 			}
+		}
+
+		signed, err := t.Wallet.SignTx(account, tx, t.ChainId)
+		if err != nil && t.Nonces != nil {
+			// The nonce will not be used.
+			t.Nonces.Reset(account.Address)
+		}
+		return signed, err
+	}
+	if t.Fees != nil {
+		feeCap, tipCap, err := t.Fees.SuggestFees(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("suggesting fees: %w", err)
+		}
+		if feeCap.Cmp(tipCap) < 0 {
+			return nil, fmt.Errorf("gas fee cap %v below tip cap %v", feeCap, tipCap)
+		}
+		opts.GasFeeCap = feeCap
+		opts.GasTipCap = tipCap
+	}
+	if t.Nonces != nil {
+		nonce, err := t.Nonces.Next(context.Background(), account.Address)
+		if err != nil {
+			return nil, fmt.Errorf("getting nonce: %w", err)
+		}
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	return opts, nil
+}
 
-			return t.Wallet.SignTx(account, tx, t.ChainId)
-		},
-	}, nil
+// applyGasOverrides returns the transaction with the set overrides applied.
+// Legacy transactions pay the fee cap as gas price.
+func applyGasOverrides(tx *types.Transaction, o control.GasOverrides) *types.Transaction {
+	gas := tx.Gas()
+	if o.GasLimit != 0 {
+		gas = o.GasLimit
+	}
+	if tx.Type() == types.LegacyTxType {
+		gasPrice := tx.GasPrice()
+		if o.GasFeeCap != nil {
+			gasPrice = o.GasFeeCap
+		}
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      gas,
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		})
+	}
+	feeCap, tipCap := tx.GasFeeCap(), tx.GasTipCap()
+	if o.GasFeeCap != nil {
+		feeCap = o.GasFeeCap
+	}
+	if o.GasTipCap != nil {
+		tipCap = o.GasTipCap
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:    tx.ChainId(),
+		Nonce:      tx.Nonce(),
+		GasTipCap:  tipCap,
+		GasFeeCap:  feeCap,
+		Gas:        gas,
+		To:         tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	})
 }
 
 // NewTransactor returns a backend that can make TransactOpts for accounts
@@ -10,6 +10,20 @@ use crate::{
 };
 use alloc::vec::Vec;
 
+/// Error returned when a message to the remote watcher or funder cannot be
+/// encoded.
+#[derive(Debug)]
+pub enum RemoteEncodeError {
+    /// The encoded message is longer than the u32 length prefix allows.
+    MessageTooLarge(usize),
+    EncodeError(EncodeError),
+}
+impl From<EncodeError> for RemoteEncodeError {
+    fn from(e: EncodeError) -> Self {
+        Self::EncodeError(e)
+    }
+}
+
 #[derive(Debug)]
 pub struct ProtoBufEncodingLayer<B: BytesBus> {
     pub bus: B,
@@ -32,6 +46,19 @@ impl<B: BytesBus> ProtoBufEncodingLayer<B> {
         msg.encode(&mut buf)?;
         Ok(buf)
     }
+
+    fn encode_remote<T: prost::Message>(msg: T) -> Result<Vec<u8>, RemoteEncodeError> {
+        // The remote watcher and funder frame their messages with a u32 for the
+        // length (4 bytes), as messages with many sub-channels or assets do not
+        // fit into a u16.
+        let len = msg.encoded_len();
+        let prefix = u32::try_from(len).map_err(|_| RemoteEncodeError::MessageTooLarge(len))?;
+
+        let mut buf = Vec::with_capacity(4 + len);
+        buf.put_slice(&prefix.to_be_bytes());
+        msg.encode(&mut buf)?;
+        Ok(buf)
+    }
 }
 
 impl<B: BytesBus> MessageBus for ProtoBufEncodingLayer<B> {
@@ -42,7 +69,7 @@ impl<B: BytesBus> MessageBus for ProtoBufEncodingLayer<B> {
         };
         let envelope = Message { msg: Some(wiremsg) };
 
-        let buf = Self::encode(envelope).unwrap();
+        let buf = Self::encode_remote(envelope).unwrap();
         self.bus.send_to_watcher(&buf);
     }
 
@@ -54,7 +81,7 @@ impl<B: BytesBus> MessageBus for ProtoBufEncodingLayer<B> {
         };
         let envelope = Message { msg: Some(wiremsg) };
 
-        let buf = Self::encode(envelope).unwrap();
+        let buf = Self::encode_remote(envelope).unwrap();
         self.bus.send_to_funder(&buf);
     }
 